@@ -79,3 +79,26 @@ func Test_Expressions(t *testing.T) {
 	}
 
 }
+
+func Test_BaselineBehaviour(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		input    map[string]*big.Int
+		expected int64
+	}{
+		{name: "division", expr: "7 / 2", input: map[string]*big.Int{}, expected: 3},
+		{name: "negative division rounds down", expr: "a / 2", input: map[string]*big.Int{"a": big.NewInt(-7)}, expected: -4},
+		{name: "negative divisor", expr: "a / b", input: map[string]*big.Int{"a": big.NewInt(-7), "b": big.NewInt(-2)}, expected: 4},
+		{name: "unknown variable is zero", expr: "a + 5", input: map[string]*big.Int{}, expected: 5},
+		{name: "nil variables", expr: "a * b + 1", input: nil, expected: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewParser(tc.expr)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, p.Eval(tc.input).Int64())
+		})
+	}
+}
@@ -1,185 +1,62 @@
 package bigexpression
 
 import (
-	"github.com/pkg/errors"
 	"math/big"
-	"regexp"
-	"strconv"
-)
-
-// https://www.engr.mun.ca/~theo/Misc/exp_parsing.htm
 
-var (
-	reSpace       = regexp.MustCompile(`\s`)
-	reOperator    = regexp.MustCompile(`\+|\*|\-|\/|\|`)
-	reDigits      = regexp.MustCompile(`^\d+$`)
-	reVariable    = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9]*$`)
-	precedenceMap = map[string]int{
-		"*": 10,
-		"/": 10,
-		"+": 5,
-		"-": 5,
-		"|": 1,
-	}
+	"github.com/mbordner/aoc2025/common/expression"
 )
 
-type Parser struct {
-	operators    []*Operator
-	operands     []interface{}
-	opPrecedence Precedence
-	start        int
-	end          int
-	expr         string
-}
-
-func (p *Parser) E() error {
-	err := p.P()
-	if err != nil {
-		return err
-	}
-	n, err := p.next()
-	if err != nil {
-		return err
-	}
-	for reOperator.MatchString(n) && IsBinary(n) {
-		p.pushOperator(n)
-		p.consume()
-
-		err = p.P()
-		if err != nil {
-			return err
-		}
+// Precedence returns > 0 if op1 > op2, or < 0 if op1 < op2, otherwise 0
+type Precedence = expression.Precedence
 
-		n, err = p.next()
-		if err != nil {
-			return err
-		}
-	}
-	for p.operators[len(p.operators)-1] != nil {
-		p.popOperator()
-	}
-	return nil
+// Parser evaluates expressions over *big.Int, it wraps an expression.Parser using a BigIntBackend with Euclidean
+// division
+type Parser struct {
+	*expression.Parser[*big.Int]
 }
 
-func (p *Parser) P() error {
-	n, err := p.next()
-	if err != nil {
-		return err
-	}
-	if reDigits.MatchString(n) {
-		int64Val, _ := strconv.ParseInt(n, 10, 64)
-		bigIntVal := big.NewInt(int64Val)
-		p.operands = append(p.operands, bigIntVal)
-		p.consume()
-	} else if reVariable.MatchString(n) {
-		p.operands = append(p.operands, variable{name: n})
-		p.consume()
-	} else if n == "(" {
-		p.consume()
-		p.operators = append(p.operators, nil)
-		err = p.E()
-		if err != nil {
-			return err
-		}
-		n, err = p.next()
-		if err != nil {
-			return err
-		}
-		if n != ")" {
-			return errors.New("expected )")
-		}
-		p.consume()
-		p.operators = p.operators[0 : len(p.operators)-1]
-	} else {
-		return errors.New("error parsing expression")
-	}
-	return nil
+// backend divides like big.Int.Div, rounding so the remainder is never negative, e.g. -7 / 2 = -4, where
+// expression.BigIntBackend truncates to -3
+type backend struct {
+	expression.BigIntBackend
 }
 
-func (p *Parser) popOperator() {
-	op := p.operators[len(p.operators)-1]
-	if IsBinary(op.op) {
-		p.operators = p.operators[0 : len(p.operators)-1]
-		op.right = p.operands[len(p.operands)-1]
-		op.left = p.operands[len(p.operands)-2]
-		p.operands = p.operands[0 : len(p.operands)-2]
-		p.operands = append(p.operands, op)
+func (backend) Div(a, b *big.Int) (*big.Int, error) {
+	if b.Sign() == 0 {
+		return nil, expression.ErrDivideByZero
 	}
+	return new(big.Int).Div(a, b), nil
 }
 
-func (p *Parser) pushOperator(op string) {
-	for p.operators[len(p.operators)-1] != nil && p.opPrecedence(op, p.operators[len(p.operators)-1].op) <= 0 {
-		p.popOperator()
-	}
-	o := Operator{}
-	o.op = op
-	p.operators = append(p.operators, &o)
-}
-
-func (p *Parser) next() (string, error) {
-
-	for p.start < len(p.expr) && reSpace.MatchString(string(p.expr[p.start])) {
-		p.start++
-	}
-
-	if p.start == len(p.expr) {
-		return "", nil
-	}
-
-	p.end = p.start
-
-	if reDigits.MatchString(string(p.expr[p.start])) {
-		for p.end < len(p.expr) && reDigits.MatchString(string(p.expr[p.end])) {
-			p.end++
-		}
-	} else if p.expr[p.start] == '(' || p.expr[p.start] == ')' {
-		p.end++
-	} else if reOperator.MatchString(string(p.expr[p.start])) {
-		p.end++
-	} else if reVariable.MatchString(string(p.expr[p.start])) {
-		for p.end < len(p.expr) && reVariable.MatchString(string(p.expr[p.start:p.end+1])) {
-			p.end++
+// Eval evaluates the expression with unknown variables as 0, panicking if it can't be evaluated
+func (p *Parser) Eval(vars map[string]*big.Int) *big.Int {
+	known := make(map[string]*big.Int, len(vars))
+	for _, name := range p.Variables() {
+		if v, exists := vars[name]; exists {
+			known[name] = v
+		} else {
+			known[name] = big.NewInt(0)
 		}
-	} else {
-		return "", errors.New("unexpected token")
 	}
-
-	return p.expr[p.start:p.end], nil
-}
-
-func (p *Parser) consume() {
-	p.start = p.end
-}
-
-func (p *Parser) Eval(vars map[string]*big.Int) *big.Int {
-	return p.operands[0].(*Operator).Eval(vars)
+	v, err := p.Parser.Eval(known)
+	if err != nil {
+		panic(err)
+	}
+	return v
 }
 
 func NewParser(expr string) (*Parser, error) {
-	p := func(op1, op2 string) int {
-		if precedenceMap[op1] > precedenceMap[op2] {
-			return 1
-		}
-		if precedenceMap[op1] < precedenceMap[op2] {
-			return -1
-		}
-		return 0
+	p, err := expression.NewBackendParser[*big.Int](expr, backend{})
+	if err != nil {
+		return nil, err
 	}
-	return NewParserWithPrecedence(expr, p)
+	return &Parser{Parser: p}, nil
 }
 
 func NewParserWithPrecedence(expr string, precedence Precedence) (*Parser, error) {
-	p := Parser{}
-	p.opPrecedence = precedence
-	p.expr = expr
-	p.operators = make([]*Operator, 0, 20)
-	p.operands = make([]interface{}, 0, 20)
-
-	p.operators = append(p.operators, nil)
-	err := p.E()
+	p, err := expression.NewBackendParserWithPrecedence[*big.Int](expr, backend{}, precedence)
 	if err != nil {
 		return nil, err
 	}
-
-	return &p, nil
+	return &Parser{Parser: p}, nil
 }
@@ -0,0 +1,72 @@
+package expression
+
+import "fmt"
+
+// Node is a node of a parsed expression tree, one of Number[T], Variable or *Operator[T]
+type Node interface {
+	String() string
+	children() []Node
+}
+
+// Number is a literal value node
+type Number[T any] struct {
+	Value T
+	text  string
+}
+
+func (n Number[T]) String() string {
+	if n.text != "" {
+		return n.text
+	}
+	return fmt.Sprint(n.Value)
+}
+
+func (n Number[T]) children() []Node {
+	return nil
+}
+
+// Visitor has Visit called for each node encountered by Walk, if the result w is not nil, Walk visits each
+// of the children of node with w, followed by a call of w.Visit(nil), mirroring go/ast
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree rooted at node in depth first order
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	for _, c := range node.children() {
+		Walk(v, c)
+	}
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the tree rooted at node in depth first order calling f for each node, if f returns
+// true, Inspect continues into the node's children followed by a call of f(nil)
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// Variables returns the distinct variable names referenced under node in the order first seen
+func Variables(node Node) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0, 4)
+	Inspect(node, func(n Node) bool {
+		if v, ok := n.(Variable); ok && !seen[v.name] {
+			seen[v.name] = true
+			names = append(names, v.name)
+		}
+		return true
+	})
+	return names
+}
@@ -0,0 +1,251 @@
+package expression
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Backend supplies the numeric type T and the arithmetic an expression is evaluated with
+type Backend[T any] interface {
+	Parse(s string) (T, error)
+	Format(v T) string
+	Add(a, b T) (T, error)
+	Sub(a, b T) (T, error)
+	Mul(a, b T) (T, error)
+	Div(a, b T) (T, error)
+	// Concat joins the decimal digits of a and b, e.g. 12 | 345 = 12345
+	Concat(a, b T) (T, error)
+}
+
+// Mode controls how the integer backends treat overflow and division that isn't exact
+type Mode int
+
+// Truncating wraps on overflow and truncates division toward zero, like Go's own operators
+const Truncating Mode = 0
+
+const (
+	// ExactDivision returns ErrNotDivisible when a division has a remainder
+	ExactDivision Mode = 1 << iota
+	// CheckedOverflow returns ErrOverflow instead of wrapping
+	CheckedOverflow
+	Strict = ExactDivision | CheckedOverflow
+)
+
+// Int64Backend evaluates with int64 values, the zero value is Truncating
+type Int64Backend struct {
+	Mode Mode
+}
+
+func (Int64Backend) Parse(s string) (int64, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid int64 %s", s)
+	}
+	return v, nil
+}
+
+func (Int64Backend) Format(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func (i Int64Backend) Add(a, b int64) (int64, error) {
+	c := a + b
+	if i.Mode&CheckedOverflow != 0 && ((a > 0 && b > 0 && c < 0) || (a < 0 && b < 0 && c >= 0)) {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+func (i Int64Backend) Sub(a, b int64) (int64, error) {
+	c := a - b
+	if i.Mode&CheckedOverflow != 0 && ((a >= 0 && b < 0 && c < 0) || (a < 0 && b > 0 && c >= 0)) {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+func (i Int64Backend) Mul(a, b int64) (int64, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	c := a * b
+	if i.Mode&CheckedOverflow != 0 && (c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64)) {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+func (i Int64Backend) Div(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, ErrDivideByZero
+	}
+	if i.Mode&CheckedOverflow != 0 && a == math.MinInt64 && b == -1 {
+		return 0, ErrOverflow
+	}
+	if i.Mode&ExactDivision != 0 && a%b != 0 {
+		return 0, ErrNotDivisible
+	}
+	return a / b, nil
+}
+
+// concatDigits parses the digits of a followed by b, a result that doesn't fit has no sensible wrapped value, so it is
+// ErrOverflow in every Mode
+func concatDigits[T any](a, b string, parse func(s string) (T, error)) (T, error) {
+	v, err := parse(a + b)
+	if err != nil {
+		var zero T
+		return zero, ErrOverflow
+	}
+	return v, nil
+}
+
+func (i Int64Backend) Concat(a, b int64) (int64, error) {
+	if b < 0 {
+		return 0, errors.New("can't concat a negative value")
+	}
+	return concatDigits(i.Format(a), i.Format(b), func(s string) (int64, error) {
+		return strconv.ParseInt(s, 10, 64)
+	})
+}
+
+// BigIntBackend evaluates with arbitrary precision *big.Int values, only ExactDivision of its Mode applies. Division
+// truncates toward zero like Int64Backend, -7 / 2 = -3, unlike the bigexpression package, which keeps big.Int.Div's
+// Euclidean -7 / 2 = -4.
+type BigIntBackend struct {
+	Mode Mode
+}
+
+func (BigIntBackend) Parse(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid integer %s", s)
+	}
+	return v, nil
+}
+
+func (BigIntBackend) Format(v *big.Int) string {
+	return v.String()
+}
+
+func (BigIntBackend) Add(a, b *big.Int) (*big.Int, error) {
+	return new(big.Int).Add(a, b), nil
+}
+
+func (BigIntBackend) Sub(a, b *big.Int) (*big.Int, error) {
+	return new(big.Int).Sub(a, b), nil
+}
+
+func (BigIntBackend) Mul(a, b *big.Int) (*big.Int, error) {
+	return new(big.Int).Mul(a, b), nil
+}
+
+func (bi BigIntBackend) Div(a, b *big.Int) (*big.Int, error) {
+	if b.Sign() == 0 {
+		return nil, ErrDivideByZero
+	}
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if bi.Mode&ExactDivision != 0 && r.Sign() != 0 {
+		return nil, ErrNotDivisible
+	}
+	return q, nil
+}
+
+func (BigIntBackend) Concat(a, b *big.Int) (*big.Int, error) {
+	if b.Sign() < 0 {
+		return nil, errors.New("can't concat a negative value")
+	}
+	v, _ := new(big.Int).SetString(a.String()+b.String(), 10)
+	return v, nil
+}
+
+// BigRatBackend evaluates exactly with *big.Rat values, so division never loses precision
+type BigRatBackend struct{}
+
+func (BigRatBackend) Parse(s string) (*big.Rat, error) {
+	v, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, errors.Errorf("invalid rational %s", s)
+	}
+	return v, nil
+}
+
+func (BigRatBackend) Format(v *big.Rat) string {
+	return v.RatString()
+}
+
+func (BigRatBackend) Add(a, b *big.Rat) (*big.Rat, error) {
+	return new(big.Rat).Add(a, b), nil
+}
+
+func (BigRatBackend) Sub(a, b *big.Rat) (*big.Rat, error) {
+	return new(big.Rat).Sub(a, b), nil
+}
+
+func (BigRatBackend) Mul(a, b *big.Rat) (*big.Rat, error) {
+	return new(big.Rat).Mul(a, b), nil
+}
+
+func (BigRatBackend) Div(a, b *big.Rat) (*big.Rat, error) {
+	if b.Sign() == 0 {
+		return nil, ErrDivideByZero
+	}
+	return new(big.Rat).Quo(a, b), nil
+}
+
+func (BigRatBackend) Concat(a, b *big.Rat) (*big.Rat, error) {
+	if !a.IsInt() || !b.IsInt() {
+		return nil, ErrNotInteger
+	}
+	v, err := BigIntBackend{Mode: Strict}.Concat(a.Num(), b.Num())
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).SetInt(v), nil
+}
+
+// Float64Backend evaluates with float64 values
+type Float64Backend struct{}
+
+func (Float64Backend) Parse(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid float64 %s", s)
+	}
+	return v, nil
+}
+
+func (Float64Backend) Format(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func (Float64Backend) Add(a, b float64) (float64, error) {
+	return a + b, nil
+}
+
+func (Float64Backend) Sub(a, b float64) (float64, error) {
+	return a - b, nil
+}
+
+func (Float64Backend) Mul(a, b float64) (float64, error) {
+	return a * b, nil
+}
+
+func (Float64Backend) Div(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, ErrDivideByZero
+	}
+	return a / b, nil
+}
+
+func (f Float64Backend) Concat(a, b float64) (float64, error) {
+	if a != math.Trunc(a) || b != math.Trunc(b) {
+		return 0, ErrNotInteger
+	}
+	if b < 0 {
+		return 0, errors.New("can't concat a negative value")
+	}
+	return f.Parse(strconv.FormatFloat(a, 'f', 0, 64) + strconv.FormatFloat(b, 'f', 0, 64))
+}
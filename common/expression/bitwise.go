@@ -0,0 +1,190 @@
+package expression
+
+import (
+	"math/bits"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// BitwiseBackend is a Backend that also supports the operators of the bitwise grammar
+type BitwiseBackend[T any] interface {
+	Backend[T]
+	And(a, b T) (T, error)
+	Or(a, b T) (T, error)
+	Xor(a, b T) (T, error)
+	Shl(a, b T) (T, error)
+	Shr(a, b T) (T, error)
+	Not(a T) (T, error)
+}
+
+type Unsigned interface {
+	uint8 | uint16 | uint32 | uint64
+}
+
+// UnsignedBackend evaluates with unsigned values of T's width, the zero value is Truncating
+type UnsignedBackend[T Unsigned] struct {
+	Mode Mode
+}
+
+// Uint16Backend is the 16 bit wide backend logic gate and wire puzzles usually want
+type Uint16Backend = UnsignedBackend[uint16]
+
+type Uint64Backend = UnsignedBackend[uint64]
+
+func (UnsignedBackend[T]) width() int {
+	return bits.Len64(uint64(^T(0)))
+}
+
+func (u UnsignedBackend[T]) Parse(s string) (T, error) {
+	v, err := strconv.ParseUint(s, 10, u.width())
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid uint%d %s", u.width(), s)
+	}
+	return T(v), nil
+}
+
+func (UnsignedBackend[T]) Format(v T) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+func (u UnsignedBackend[T]) Add(a, b T) (T, error) {
+	c := a + b
+	if u.Mode&CheckedOverflow != 0 && c < a {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+func (u UnsignedBackend[T]) Sub(a, b T) (T, error) {
+	if u.Mode&CheckedOverflow != 0 && b > a {
+		return 0, ErrOverflow
+	}
+	return a - b, nil
+}
+
+func (u UnsignedBackend[T]) Mul(a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	c := a * b
+	if u.Mode&CheckedOverflow != 0 && c/b != a {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+func (u UnsignedBackend[T]) Div(a, b T) (T, error) {
+	if b == 0 {
+		return 0, ErrDivideByZero
+	}
+	if u.Mode&ExactDivision != 0 && a%b != 0 {
+		return 0, ErrNotDivisible
+	}
+	return a / b, nil
+}
+
+func (u UnsignedBackend[T]) Concat(a, b T) (T, error) {
+	return concatDigits(u.Format(a), u.Format(b), func(s string) (T, error) {
+		v, err := strconv.ParseUint(s, 10, u.width())
+		return T(v), err
+	})
+}
+
+func (UnsignedBackend[T]) And(a, b T) (T, error) {
+	return a & b, nil
+}
+
+func (UnsignedBackend[T]) Or(a, b T) (T, error) {
+	return a | b, nil
+}
+
+func (UnsignedBackend[T]) Xor(a, b T) (T, error) {
+	return a ^ b, nil
+}
+
+// Shl shifts a left by b bits, with CheckedOverflow it returns ErrOverflow if any set bit is shifted out
+func (u UnsignedBackend[T]) Shl(a, b T) (T, error) {
+	c := a << b
+	if u.Mode&CheckedOverflow != 0 && c>>b != a {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+func (UnsignedBackend[T]) Shr(a, b T) (T, error) {
+	return a >> b, nil
+}
+
+func (UnsignedBackend[T]) Not(a T) (T, error) {
+	return ^a, nil
+}
+
+// bitwiseOperators maps the symbol and word forms of the bitwise grammar's binary operators to the word form
+// operators are stored as
+var bitwiseOperators = map[string]string{
+	"&":      "AND",
+	"AND":    "AND",
+	"|":      "OR",
+	"OR":     "OR",
+	"^":      "XOR",
+	"XOR":    "XOR",
+	"<<":     "LSHIFT",
+	"LSHIFT": "LSHIFT",
+	">>":     "RSHIFT",
+	"RSHIFT": "RSHIFT",
+}
+
+// bitwisePrecedenceMap follows C, with the arithmetic operators binding tighter than the bitwise ones
+var bitwisePrecedenceMap = map[string]int{
+	"*":      10,
+	"/":      10,
+	"+":      9,
+	"-":      9,
+	"LSHIFT": 8,
+	"RSHIFT": 8,
+	"AND":    7,
+	"XOR":    6,
+	"OR":     5,
+}
+
+func bitwisePrecedence(op1, op2 string) int {
+	if bitwisePrecedenceMap[op1] > bitwisePrecedenceMap[op2] {
+		return 1
+	}
+	if bitwisePrecedenceMap[op1] < bitwisePrecedenceMap[op2] {
+		return -1
+	}
+	return 0
+}
+
+func isUnaryNot(token string) bool {
+	return token == "NOT" || token == "~"
+}
+
+func applyBitwiseOperator[T any](backend Backend[T], op string, l, r T) (T, error) {
+	var zero T
+	bb, ok := backend.(BitwiseBackend[T])
+	if !ok {
+		return zero, errors.Errorf("operator %s needs a bitwise backend", op)
+	}
+	switch op {
+	case "AND":
+		return bb.And(l, r)
+	case "OR":
+		return bb.Or(l, r)
+	case "XOR":
+		return bb.Xor(l, r)
+	case "LSHIFT":
+		return bb.Shl(l, r)
+	case "RSHIFT":
+		return bb.Shr(l, r)
+	}
+	return zero, errors.Errorf("unknown operator %s", op)
+}
+
+// NewBitwiseParser parses expr with the bitwise grammar, which adds AND (&), OR (|), XOR (^), LSHIFT (<<),
+// RSHIFT (>>) and the unary NOT (~) to the arithmetic operators
+func NewBitwiseParser[T any](expr string, backend BitwiseBackend[T]) (*Parser[T], error) {
+	return newParser[T](expr, backend, bitwisePrecedence, true)
+}
@@ -0,0 +1,106 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Bitwise(t *testing.T) {
+	wires := map[string]uint16{"x": 123, "y": 456}
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected uint16
+	}{
+		{name: "and", expr: "x AND y", expected: 72},
+		{name: "and symbol", expr: "x & y", expected: 72},
+		{name: "or", expr: "x OR y", expected: 507},
+		{name: "or symbol", expr: "x | y", expected: 507},
+		{name: "xor", expr: "x XOR y", expected: 435},
+		{name: "xor symbol", expr: "x ^ y", expected: 435},
+		{name: "lshift", expr: "x LSHIFT 2", expected: 492},
+		{name: "lshift symbol", expr: "x << 2", expected: 492},
+		{name: "rshift", expr: "y RSHIFT 2", expected: 114},
+		{name: "rshift symbol", expr: "y >> 2", expected: 114},
+		{name: "not", expr: "NOT x", expected: 65412},
+		{name: "not symbol", expr: "~y", expected: 65079},
+		{name: "not binds tightest", expr: "NOT x AND y", expected: 384},
+		{name: "precedence", expr: "1 | 2 ^ 3 & 6 << 1", expected: 3},
+		{name: "arithmetic before shift", expr: "1 << 2 + 1", expected: 8},
+		{name: "wraps", expr: "x - y", expected: 65203},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewBitwiseParser[uint16](tc.expr, Uint16Backend{})
+			assert.Nil(t, err)
+			v, err := p.Eval(wires)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+}
+
+func Test_BitwiseWidths(t *testing.T) {
+	p, err := NewBitwiseParser[uint64]("NOT x >> 60", Uint64Backend{})
+	assert.Nil(t, err)
+	v, err := p.Eval(map[string]uint64{"x": 0})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(15), v)
+	assert.Equal(t, "((x XOR 18446744073709551615) RSHIFT 60)", p.String())
+
+	p, err = NewBitwiseParser[uint64]("x << 1", Uint64Backend{Mode: CheckedOverflow})
+	assert.Nil(t, err)
+	_, err = p.Eval(map[string]uint64{"x": 1 << 63})
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	_, err = NewBitwiseParser[uint16]("70000", Uint16Backend{})
+	var pe *ParseError
+	assert.ErrorAs(t, err, &pe)
+
+	s, err := NewBitwiseParser[uint16]("x - y", Uint16Backend{Mode: Strict})
+	assert.Nil(t, err)
+	_, err = s.Eval(map[string]uint16{"x": 1, "y": 2})
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func Test_BitwiseGrammar(t *testing.T) {
+	// the arithmetic grammar keeps | as concat and rejects the bitwise operators
+	p, err := NewParser("AND | 2")
+	assert.Nil(t, err)
+	v, err := p.Eval(map[string]int64{"AND": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(12), v)
+
+	_, err = NewParser("a & b")
+	var pe *ParseError
+	if assert.ErrorAs(t, err, &pe) {
+		assert.Equal(t, "&", pe.Token)
+	}
+
+	// the words are reserved in the bitwise grammar
+	_, err = NewBitwiseParser[uint16]("AND & 2", Uint16Backend{})
+	assert.ErrorAs(t, err, &pe)
+
+	// bitwise operators need a bitwise backend
+	b, err := NewBitwiseParser[uint16]("x XOR 3", Uint16Backend{})
+	assert.Nil(t, err)
+	_, err = b.EvalRat(map[string]uint16{"x": 1})
+	assert.ErrorContains(t, err, "needs a bitwise backend")
+
+	// XOR inverts to itself
+	y, err := NewBitwiseParser[uint16]("y + 0", Uint16Backend{})
+	assert.Nil(t, err)
+	x, inverse, err := b.RootOperator().InverseOperationToVariableExpression(y.RootOperator())
+	assert.Nil(t, err)
+	assert.Equal(t, "x", x.Name())
+	v16, err := inverse.Eval(map[string]uint16{"y": 6})
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(5), v16)
+
+	i, err := b.EvalInterval(map[string]Interval{"x": NewInterval(0, 1)})
+	assert.Nil(t, err)
+	assert.Equal(t, Unbounded(), i)
+}
@@ -0,0 +1,47 @@
+package expression
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrDivideByZero    = errors.New("divide by zero")
+	ErrNotDivisible    = errors.New("not divisible")
+	ErrOverflow        = errors.New("integer overflow")
+	ErrNotInteger      = errors.New("not an integer")
+	ErrInvalidOperands = errors.New("invalid operands")
+)
+
+// ParseError reports where in the expression parsing failed
+type ParseError struct {
+	Msg    string
+	Offset int    // byte offset of Token in the expression
+	Column int    // 1 based column of Token, counting runes
+	Token  string // the offending token, empty at the end of the expression
+	Err    error  // underlying cause, if any
+}
+
+func (e *ParseError) Error() string {
+	token := e.Token
+	if token == "" {
+		token = "end of expression"
+	} else {
+		token = fmt.Sprintf("%q", token)
+	}
+	msg := fmt.Sprintf("%s at column %d near %s", e.Msg, e.Column, token)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func newParseError(expr string, offset int, token string, err error, msg string) *ParseError {
+	return &ParseError{Msg: msg, Offset: offset, Column: utf8.RuneCountInString(expr[:offset]) + 1, Token: token, Err: err}
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -1,6 +1,7 @@
 package expression
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -80,6 +81,24 @@ func Test_Expressions(t *testing.T) {
 			input:    map[string]int64{"var1": int64(3), "var2": int64(4)},
 			expected: int64(12),
 		},
+		{
+			name:     "subtraction without spaces",
+			expr:     "5-3",
+			input:    make(map[string]int64),
+			expected: int64(2),
+		},
+		{
+			name:     "negative variable",
+			expr:     "2 * -(var1 + 1)",
+			input:    map[string]int64{"var1": int64(3)},
+			expected: int64(-8),
+		},
+		{
+			name:     "concat",
+			expr:     "12 | 3 + 4",
+			input:    make(map[string]int64),
+			expected: int64(127),
+		},
 	}
 
 	for _, tc := range tests {
@@ -99,3 +118,229 @@ func Test_Expressions(t *testing.T) {
 	}
 
 }
+
+func Test_Backends(t *testing.T) {
+	expr := "(a + 3) * b / 2 | 5"
+
+	ip, err := NewParser(expr)
+	assert.Nil(t, err)
+	iv, err := ip.Eval(map[string]int64{"a": 1, "b": 6})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(125), iv)
+
+	bp, err := NewBigIntParser(expr)
+	assert.Nil(t, err)
+	bv, err := bp.Eval(map[string]*big.Int{"a": big.NewInt(1), "b": big.NewInt(6)})
+	assert.Nil(t, err)
+	assert.Equal(t, "125", bv.String())
+
+	rp, err := NewBigRatParser("(a + 3) * b / 2")
+	assert.Nil(t, err)
+	rv, err := rp.Eval(map[string]*big.Rat{"a": big.NewRat(1, 1), "b": big.NewRat(5, 1)})
+	assert.Nil(t, err)
+	assert.Equal(t, "10", rv.RatString())
+	rv, err = rp.Eval(map[string]*big.Rat{"a": big.NewRat(0, 1), "b": big.NewRat(1, 1)})
+	assert.Nil(t, err)
+	assert.Equal(t, "3/2", rv.RatString())
+
+	fp, err := NewFloat64Parser("a * 1.5 | 2")
+	assert.Nil(t, err)
+	fv, err := fp.Eval(map[string]float64{"a": 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 32.0, fv)
+	_, err = fp.Eval(map[string]float64{"a": 1})
+	assert.ErrorIs(t, err, ErrNotInteger)
+}
+
+func Test_Int64Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected error
+	}{
+		{name: "overflow add", expr: "9223372036854775807 + 1", expected: ErrOverflow},
+		{name: "overflow sub", expr: "-9223372036854775807 - 2", expected: ErrOverflow},
+		{name: "overflow mul", expr: "4611686018427387904 * 2", expected: ErrOverflow},
+		{name: "overflow concat", expr: "922337203685477580 | 8", expected: ErrOverflow},
+		{name: "not divisible", expr: "7 / 2", expected: ErrNotDivisible},
+		{name: "divide by zero", expr: "7 / 0", expected: ErrDivideByZero},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewParser(tc.expr)
+			assert.Nil(t, err)
+			_, err = p.Eval(map[string]int64{})
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func Test_StringAndEvalKnown(t *testing.T) {
+	p, err := NewBigIntParser("a * (b + 2) - c")
+	assert.Nil(t, err)
+	assert.Equal(t, "((a * (b + 2)) - c)", p.String())
+
+	_, err = p.EvalKnown(map[string]*big.Int{"b": big.NewInt(3)})
+	assert.NotNil(t, err)
+	assert.Equal(t, "((a * 5) - c)", p.String())
+
+	v, err := p.Eval(map[string]*big.Int{"a": big.NewInt(2), "c": big.NewInt(1)})
+	assert.Nil(t, err)
+	assert.Equal(t, "9", v.String())
+}
+
+func Test_Inverse(t *testing.T) {
+	p, err := NewBigRatParser("(x * 3 + 2) / 4")
+	assert.Nil(t, err)
+
+	y, err := NewBigRatParser("y + 0")
+	assert.Nil(t, err)
+
+	v, inverse, err := p.RootOperator().InverseOperationToVariableExpression(y.RootOperator())
+	assert.Nil(t, err)
+	assert.Equal(t, "x", v.Name())
+
+	x, err := inverse.Eval(map[string]*big.Rat{"y": big.NewRat(5, 1)})
+	assert.Nil(t, err)
+	assert.Equal(t, "6", x.RatString())
+}
+
+func Test_Modes(t *testing.T) {
+	assert.Equal(t, Mode(1), ExactDivision)
+	assert.Equal(t, Mode(2), CheckedOverflow)
+	assert.Equal(t, Mode(3), Strict)
+
+	tests := []struct {
+		name     string
+		expr     string
+		mode     Mode
+		expected int64
+		err      error
+	}{
+		{name: "truncating division", expr: "7 / 2", mode: Truncating, expected: 3},
+		{name: "truncating negative division", expr: "-7 / 2", mode: Truncating, expected: -3},
+		{name: "truncating overflow wraps", expr: "9223372036854775807 + 1", mode: Truncating, expected: -9223372036854775808},
+		{name: "exact division", expr: "7 / 2", mode: ExactDivision, err: ErrNotDivisible},
+		{name: "exact division ignores overflow", expr: "9223372036854775807 * 2", mode: ExactDivision, expected: -2},
+		{name: "checked overflow", expr: "9223372036854775807 * 2", mode: CheckedOverflow, err: ErrOverflow},
+		{name: "checked overflow truncates division", expr: "7 / 2", mode: CheckedOverflow, expected: 3},
+		{name: "strict", expr: "(-9223372036854775807 - 1) / -1", mode: Strict, err: ErrOverflow},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewParser(tc.expr)
+			assert.Nil(t, err)
+			v, err := p.EvalWith(Int64Backend{Mode: tc.mode}, map[string]int64{})
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expected, v)
+			}
+		})
+	}
+}
+
+func Test_EvalRat(t *testing.T) {
+	p, err := NewParser("(b0 - 3 * b1) / 2 + b1")
+	assert.Nil(t, err)
+
+	r, err := p.EvalRat(map[string]int64{"b0": 10, "b1": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, "9/2", r.RatString())
+
+	_, err = p.EvalExact(map[string]int64{"b0": 10, "b1": 1})
+	assert.ErrorIs(t, err, ErrNotInteger)
+
+	v, err := p.EvalExact(map[string]int64{"b0": 11, "b1": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), v)
+
+	// intermediate values that don't fit in int64 are fine as long as the result does
+	p, err = NewParser("a * a / a")
+	assert.Nil(t, err)
+	v, err = p.EvalExact(map[string]int64{"a": 9223372036854775807})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9223372036854775807), v)
+
+	f, err := NewFloat64Parser("x / 3")
+	assert.Nil(t, err)
+	r, err = EvalAs[float64, *big.Rat](f, BigRatBackend{}, map[string]float64{"x": 0.5})
+	assert.Nil(t, err)
+	assert.Equal(t, "1/6", r.RatString())
+}
+
+func Test_ParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   string
+		msg    string
+		offset int
+		column int
+		token  string
+	}{
+		{name: "missing operand", expr: "1 + * 2", msg: "expected a number, variable or (", offset: 4, column: 5, token: "*"},
+		{name: "missing close paren", expr: "(1 + 2", msg: "expected )", offset: 6, column: 7, token: ""},
+		{name: "unexpected character", expr: "a + $b", msg: "unexpected character", offset: 4, column: 5, token: "$"},
+		{name: "trailing token", expr: "a + b c", msg: "unexpected token", offset: 6, column: 7, token: "c"},
+		{name: "unbalanced paren", expr: "a + b)", msg: "unexpected token", offset: 5, column: 6, token: ")"},
+		{name: "column counts runes", expr: "1 + é + 2", msg: "unexpected character", offset: 4, column: 5, token: "é"},
+		{name: "invalid number", expr: "2 * -1.5", msg: "invalid number", offset: 4, column: 5, token: "-1.5"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewParser(tc.expr)
+			var pe *ParseError
+			if assert.ErrorAs(t, err, &pe) {
+				assert.Equal(t, tc.msg, pe.Msg)
+				assert.Equal(t, tc.offset, pe.Offset)
+				assert.Equal(t, tc.column, pe.Column)
+				assert.Equal(t, tc.token, pe.Token)
+			}
+		})
+	}
+
+	_, err := NewParser("1 + * 2")
+	assert.EqualError(t, err, `expected a number, variable or ( at column 5 near "*"`)
+}
+
+func Test_Walk(t *testing.T) {
+	p, err := NewParser("a * (b + 2) - a")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"a", "b"}, p.Variables())
+
+	var ops, numbers []string
+	Inspect(p.Root(), func(n Node) bool {
+		switch v := n.(type) {
+		case *Operator[int64]:
+			ops = append(ops, v.Op())
+		case Number[int64]:
+			numbers = append(numbers, v.String())
+			assert.Equal(t, int64(2), v.Value)
+		}
+		return true
+	})
+	assert.Equal(t, []string{"-", "*", "+"}, ops)
+	assert.Equal(t, []string{"2"}, numbers)
+
+	// returning false skips the children
+	count := 0
+	Inspect(p.Root(), func(n Node) bool {
+		if n != nil {
+			count++
+		}
+		_, isOp := n.(*Operator[int64])
+		return n == p.Root() || !isOp
+	})
+	assert.Equal(t, 3, count)
+
+	o := NewOperator[int64](Int64Backend{}, "+", NewVariable("x"), Number[int64]{Value: 3})
+	assert.Equal(t, "(x + 3)", o.String())
+	v, err := o.Eval(map[string]int64{"x": 4})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), v)
+}
@@ -0,0 +1,369 @@
+package expression
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// Interval is a closed range of values [Lo, Hi], a nil Lo is -infinity and a nil Hi is +infinity
+type Interval struct {
+	Lo *big.Rat
+	Hi *big.Rat
+}
+
+func NewInterval(lo, hi int64) Interval {
+	return Interval{Lo: big.NewRat(lo, 1), Hi: big.NewRat(hi, 1)}
+}
+
+// Unbounded returns the interval of every value
+func Unbounded() Interval {
+	return Interval{}
+}
+
+func (i Interval) String() string {
+	lo, hi := "-inf", "+inf"
+	if i.Lo != nil {
+		lo = i.Lo.RatString()
+	}
+	if i.Hi != nil {
+		hi = i.Hi.RatString()
+	}
+	return "[" + lo + ", " + hi + "]"
+}
+
+func (i Interval) Contains(v *big.Rat) bool {
+	return (i.Lo == nil || i.Lo.Cmp(v) <= 0) && (i.Hi == nil || v.Cmp(i.Hi) <= 0)
+}
+
+// Negative returns true if every value in the interval is < 0
+func (i Interval) Negative() bool {
+	return i.Hi != nil && i.Hi.Sign() < 0
+}
+
+// NonNegative returns true if every value in the interval is >= 0
+func (i Interval) NonNegative() bool {
+	return i.Lo != nil && i.Lo.Sign() >= 0
+}
+
+// extended is a value on the extended number line, inf is -1 or 1 for -infinity or +infinity
+type extended struct {
+	inf int
+	v   *big.Rat
+}
+
+func (i Interval) lower() extended {
+	if i.Lo == nil {
+		return extended{inf: -1}
+	}
+	return extended{v: i.Lo}
+}
+
+func (i Interval) upper() extended {
+	if i.Hi == nil {
+		return extended{inf: 1}
+	}
+	return extended{v: i.Hi}
+}
+
+func intervalOf(lo, hi extended) Interval {
+	i := Interval{}
+	if lo.inf == 0 {
+		i.Lo = lo.v
+	}
+	if hi.inf == 0 {
+		i.Hi = hi.v
+	}
+	return i
+}
+
+func (e extended) sign() int {
+	if e.inf != 0 {
+		return e.inf
+	}
+	return e.v.Sign()
+}
+
+func (e extended) cmp(o extended) int {
+	if e.inf != 0 || o.inf != 0 {
+		switch {
+		case e.inf < o.inf:
+			return -1
+		case e.inf > o.inf:
+			return 1
+		}
+		return 0
+	}
+	return e.v.Cmp(o.v)
+}
+
+func (e extended) neg() extended {
+	if e.inf != 0 {
+		return extended{inf: -e.inf}
+	}
+	return extended{v: new(big.Rat).Neg(e.v)}
+}
+
+// add is only called with operands that can't be infinities of opposite signs
+func (e extended) add(o extended) extended {
+	if e.inf != 0 {
+		return e
+	}
+	if o.inf != 0 {
+		return o
+	}
+	return extended{v: new(big.Rat).Add(e.v, o.v)}
+}
+
+// mul treats 0 * infinity as 0, which holds for the closed intervals bounds come from
+func (e extended) mul(o extended) extended {
+	if e.sign() == 0 || o.sign() == 0 {
+		return extended{v: new(big.Rat)}
+	}
+	if e.inf != 0 || o.inf != 0 {
+		return extended{inf: e.sign() * o.sign()}
+	}
+	return extended{v: new(big.Rat).Mul(e.v, o.v)}
+}
+
+// inv returns 1/e for a non zero e, with 1/infinity being 0
+func (e extended) inv() extended {
+	if e.inf != 0 {
+		return extended{v: new(big.Rat)}
+	}
+	return extended{v: new(big.Rat).Inv(e.v)}
+}
+
+func (i Interval) isZero() bool {
+	return i.Lo != nil && i.Hi != nil && i.Lo.Sign() == 0 && i.Hi.Sign() == 0
+}
+
+func addIntervals(a, b Interval) Interval {
+	return intervalOf(a.lower().add(b.lower()), a.upper().add(b.upper()))
+}
+
+func subIntervals(a, b Interval) Interval {
+	return intervalOf(a.lower().add(b.upper().neg()), a.upper().add(b.lower().neg()))
+}
+
+func mulIntervals(a, b Interval) Interval {
+	products := []extended{
+		a.lower().mul(b.lower()),
+		a.lower().mul(b.upper()),
+		a.upper().mul(b.lower()),
+		a.upper().mul(b.upper()),
+	}
+	lo, hi := products[0], products[0]
+	for _, p := range products[1:] {
+		if p.cmp(lo) < 0 {
+			lo = p
+		}
+		if p.cmp(hi) > 0 {
+			hi = p
+		}
+	}
+	return intervalOf(lo, hi)
+}
+
+// divIntervals divides a by b, for integer backends b's values are whole numbers, so a b touching or straddling zero
+// is split into its negative and positive parts with zero left out
+func divIntervals(a, b Interval, integer bool) (Interval, error) {
+	lo, hi := b.lower(), b.upper()
+	var reciprocal Interval
+	switch {
+	case b.isZero():
+		return Interval{}, ErrDivideByZero
+	case lo.sign() > 0 || hi.sign() < 0:
+		reciprocal = intervalOf(hi.inv(), lo.inv())
+	case integer:
+		return divNonZero(a, b)
+	case lo.sign() == 0:
+		reciprocal = intervalOf(hi.inv(), extended{inf: 1})
+	case hi.sign() == 0:
+		reciprocal = intervalOf(extended{inf: -1}, lo.inv())
+	default:
+		// b straddles zero, so quotients approach both infinities unless a is exactly 0
+		if a.isZero() {
+			return a, nil
+		}
+		return Unbounded(), nil
+	}
+	return mulIntervals(a, reciprocal), nil
+}
+
+// divNonZero divides a by the whole numbers of b other than zero, b's parts [lo, -1] and [1, hi]
+func divNonZero(a, b Interval) (Interval, error) {
+	one, minusOne := big.NewRat(1, 1), big.NewRat(-1, 1)
+	var parts []Interval
+	if b.Lo == nil || b.Lo.Cmp(minusOne) <= 0 {
+		parts = append(parts, Interval{Lo: b.Lo, Hi: minusOne})
+	}
+	if b.Hi == nil || b.Hi.Cmp(one) >= 0 {
+		parts = append(parts, Interval{Lo: one, Hi: b.Hi})
+	}
+	if len(parts) == 0 {
+		return Interval{}, ErrDivideByZero
+	}
+	q, _ := divIntervals(a, parts[0], true)
+	for _, part := range parts[1:] {
+		p, _ := divIntervals(a, part, true)
+		q = hull(q, p)
+	}
+	return q, nil
+}
+
+// hull returns the smallest interval holding both a and b
+func hull(a, b Interval) Interval {
+	lo, hi := a.lower(), a.upper()
+	if b.lower().cmp(lo) < 0 {
+		lo = b.lower()
+	}
+	if b.upper().cmp(hi) > 0 {
+		hi = b.upper()
+	}
+	return intervalOf(lo, hi)
+}
+
+// concatIntervals is increasing in both operands for a >= 0 and decreasing in b for a <= 0
+func concatIntervals(a, b Interval) (Interval, error) {
+	if a.Lo == nil || a.Hi == nil || b.Lo == nil || b.Hi == nil || b.Lo.Sign() < 0 {
+		return Unbounded(), nil
+	}
+	concat := func(x, y *big.Rat) (*big.Rat, error) {
+		return BigRatBackend{}.Concat(x, y)
+	}
+	var lo, hi *big.Rat
+	var err error
+	switch {
+	case a.Lo.Sign() >= 0:
+		if lo, err = concat(a.Lo, b.Lo); err == nil {
+			hi, err = concat(a.Hi, b.Hi)
+		}
+	case a.Hi.Sign() <= 0:
+		if lo, err = concat(a.Lo, b.Hi); err == nil {
+			hi, err = concat(a.Hi, b.Lo)
+		}
+	default:
+		if lo, err = concat(a.Lo, b.Hi); err == nil {
+			hi, err = concat(a.Hi, b.Hi)
+		}
+	}
+	if err != nil {
+		return Unbounded(), nil
+	}
+	return Interval{Lo: lo, Hi: hi}, nil
+}
+
+// divisionRounder is implemented by backends whose division results are rounded to whole numbers
+type divisionRounder interface {
+	roundQuotient(q Interval) (Interval, error)
+}
+
+func truncRat(r *big.Rat) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Quo(r.Num(), r.Denom()))
+}
+
+func floorRat(r *big.Rat) *big.Rat {
+	q := new(big.Int).Div(r.Num(), r.Denom()) // Euclidean division floors for a positive denominator
+	return new(big.Rat).SetInt(q)
+}
+
+func ceilRat(r *big.Rat) *big.Rat {
+	return new(big.Rat).Neg(floorRat(new(big.Rat).Neg(r)))
+}
+
+// roundIntegerQuotient narrows a real quotient interval to the integer quotients mode can produce
+func roundIntegerQuotient(mode Mode, q Interval) (Interval, error) {
+	r := Interval{}
+	if mode&ExactDivision != 0 {
+		if q.Lo != nil {
+			r.Lo = ceilRat(q.Lo)
+		}
+		if q.Hi != nil {
+			r.Hi = floorRat(q.Hi)
+		}
+		if r.Lo != nil && r.Hi != nil && r.Lo.Cmp(r.Hi) > 0 {
+			return r, ErrNotDivisible
+		}
+		return r, nil
+	}
+	// truncation is monotonic, so the bounds truncate too
+	if q.Lo != nil {
+		r.Lo = truncRat(q.Lo)
+	}
+	if q.Hi != nil {
+		r.Hi = truncRat(q.Hi)
+	}
+	return r, nil
+}
+
+func (i Int64Backend) roundQuotient(q Interval) (Interval, error) {
+	return roundIntegerQuotient(i.Mode, q)
+}
+
+func (bi BigIntBackend) roundQuotient(q Interval) (Interval, error) {
+	return roundIntegerQuotient(bi.Mode, q)
+}
+
+func (u UnsignedBackend[T]) roundQuotient(q Interval) (Interval, error) {
+	return roundIntegerQuotient(u.Mode, q)
+}
+
+func evalIntervalOperand[T any](operand Node, backend Backend[T], bounds map[string]Interval) (Interval, error) {
+	switch t := operand.(type) {
+	case Variable:
+		return lookupVariable(t, bounds)
+	case Number[T]:
+		v, err := BigRatBackend{}.Parse(backend.Format(t.Value))
+		if err != nil {
+			return Interval{}, err
+		}
+		return Interval{Lo: v, Hi: v}, nil
+	case *Operator[T]:
+		l, err := evalIntervalOperand(t.left, backend, bounds)
+		if err != nil {
+			return Interval{}, err
+		}
+		r, err := evalIntervalOperand(t.right, backend, bounds)
+		if err != nil {
+			return Interval{}, err
+		}
+		switch t.op {
+		case "+":
+			return addIntervals(l, r), nil
+		case "-":
+			return subIntervals(l, r), nil
+		case "*":
+			return mulIntervals(l, r), nil
+		case "/":
+			rounder, integer := backend.(divisionRounder)
+			q, err := divIntervals(l, r, integer)
+			if err != nil {
+				return q, err
+			}
+			if integer {
+				return rounder.roundQuotient(q)
+			}
+			return q, nil
+		case "|":
+			return concatIntervals(l, r)
+		case "AND", "OR", "XOR", "LSHIFT", "RSHIFT":
+			return Unbounded(), nil
+		}
+		return Interval{}, errors.Errorf("unknown operator %s", t.op)
+	}
+	return Interval{}, ErrInvalidOperands
+}
+
+// EvalInterval returns the tightest interval interval arithmetic can give for the operator's value when each
+// variable lies within its bounds, integer backends round division the way their Mode does
+func (o *Operator[T]) EvalInterval(bounds map[string]Interval) (Interval, error) {
+	return evalIntervalOperand(o, o.backend, bounds)
+}
+
+func (p *Parser[T]) EvalInterval(bounds map[string]Interval) (Interval, error) {
+	if len(p.operands) != 1 {
+		return Interval{}, ErrInvalidOperands
+	}
+	return evalIntervalOperand(p.operands[0], p.backend, bounds)
+}
@@ -0,0 +1,121 @@
+package expression
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EvalInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		bounds   map[string]Interval
+		expected string
+	}{
+		{name: "constant", expr: "3", expected: "[3, 3]"},
+		{name: "addition", expr: "a + b", bounds: map[string]Interval{"a": NewInterval(1, 2), "b": NewInterval(-5, 3)}, expected: "[-4, 5]"},
+		{name: "subtraction", expr: "a - b", bounds: map[string]Interval{"a": NewInterval(1, 2), "b": NewInterval(-5, 3)}, expected: "[-2, 7]"},
+		{name: "multiplication straddling zero", expr: "a * b", bounds: map[string]Interval{"a": NewInterval(-2, 3), "b": NewInterval(-5, 4)}, expected: "[-15, 12]"},
+		{name: "multiplication negative", expr: "a * b", bounds: map[string]Interval{"a": NewInterval(-2, -1), "b": NewInterval(3, 4)}, expected: "[-8, -3]"},
+		{name: "exact division rounds inward", expr: "a / 2", bounds: map[string]Interval{"a": NewInterval(-3, 7)}, expected: "[-1, 3]"},
+		{name: "division by positive range", expr: "a / b", bounds: map[string]Interval{"a": NewInterval(6, 12), "b": NewInterval(2, 3)}, expected: "[2, 6]"},
+		{name: "division by range straddling zero", expr: "a / b", bounds: map[string]Interval{"a": NewInterval(1, 2), "b": NewInterval(-1, 1)}, expected: "[-2, 2]"},
+		{name: "zero divided by range straddling zero", expr: "0 / b", bounds: map[string]Interval{"b": NewInterval(-1, 1)}, expected: "[0, 0]"},
+		{name: "division by range starting at zero", expr: "a / b", bounds: map[string]Interval{"a": NewInterval(1, 2), "b": NewInterval(0, 2)}, expected: "[1, 2]"},
+		{name: "division by range ending at zero", expr: "a / b", bounds: map[string]Interval{"a": NewInterval(1, 2), "b": NewInterval(-2, 0)}, expected: "[-2, -1]"},
+		{name: "division by unbounded range", expr: "a / b", bounds: map[string]Interval{"a": NewInterval(-4, 2), "b": Unbounded()}, expected: "[-4, 4]"},
+		{name: "unbounded variable", expr: "a * 0 + b", bounds: map[string]Interval{"a": Unbounded(), "b": {Lo: big.NewRat(1, 1)}}, expected: "[1, +inf]"},
+		{name: "concat", expr: "a | b", bounds: map[string]Interval{"a": NewInterval(1, 2), "b": NewInterval(5, 10)}, expected: "[15, 210]"},
+		{name: "pivot expression", expr: "(20 - b3 - b4 * 2) / 2", bounds: map[string]Interval{"b3": NewInterval(0, 4), "b4": NewInterval(0, 3)}, expected: "[5, 10]"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewParser(tc.expr)
+			assert.Nil(t, err)
+			i, err := p.EvalInterval(tc.bounds)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, i.String())
+		})
+	}
+}
+
+func Test_EvalIntervalBackends(t *testing.T) {
+	bounds := map[string]Interval{"a": NewInterval(-7, 7)}
+
+	p, err := NewBigRatParser("a / 2")
+	assert.Nil(t, err)
+	i, err := p.EvalInterval(bounds)
+	assert.Nil(t, err)
+	assert.Equal(t, "[-7/2, 7/2]", i.String())
+
+	ip, err := NewBackendParser[int64]("a / 2", Int64Backend{})
+	assert.Nil(t, err)
+	i, err = ip.EvalInterval(bounds)
+	assert.Nil(t, err)
+	assert.Equal(t, "[-3, 3]", i.String())
+
+	ip, err = NewParser("a / 4")
+	assert.Nil(t, err)
+	_, err = ip.EvalInterval(map[string]Interval{"a": NewInterval(1, 3)})
+	assert.ErrorIs(t, err, ErrNotDivisible)
+
+	_, err = ip.EvalInterval(map[string]Interval{})
+	assert.NotNil(t, err)
+
+	ip, err = NewParser("a / b")
+	assert.Nil(t, err)
+	_, err = ip.EvalInterval(map[string]Interval{"a": NewInterval(1, 3), "b": NewInterval(0, 0)})
+	assert.ErrorIs(t, err, ErrDivideByZero)
+
+	// integer divisors skip zero, real ones get arbitrarily close to it
+	straddling := map[string]Interval{"a": NewInterval(1, 2), "b": NewInterval(-1, 1)}
+	bp, err := NewBigIntParser("a / b")
+	assert.Nil(t, err)
+	i, err = bp.EvalInterval(straddling)
+	assert.Nil(t, err)
+	assert.Equal(t, "[-2, 2]", i.String())
+
+	rp, err := NewBigRatParser("a / b")
+	assert.Nil(t, err)
+	i, err = rp.EvalInterval(straddling)
+	assert.Nil(t, err)
+	assert.Equal(t, "[-inf, +inf]", i.String())
+
+	up, err := NewBitwiseParser[uint16]("a / b", Uint16Backend{})
+	assert.Nil(t, err)
+	i, err = up.EvalInterval(map[string]Interval{"a": NewInterval(1, 7), "b": NewInterval(0, 2)})
+	assert.Nil(t, err)
+	assert.Equal(t, "[0, 7]", i.String())
+}
+
+func Test_EvalIntervalContainsSamples(t *testing.T) {
+	p, err := NewBigRatParser("(a - 2) * (b + 1) / (a + 4) - b")
+	assert.Nil(t, err)
+	bounds := map[string]Interval{"a": NewInterval(-3, 5), "b": NewInterval(-2, 3)}
+	i, err := p.EvalInterval(bounds)
+	assert.Nil(t, err)
+
+	for a := int64(-3); a <= 5; a++ {
+		for b := int64(-2); b <= 3; b++ {
+			v, err := p.Eval(map[string]*big.Rat{"a": big.NewRat(a, 1), "b": big.NewRat(b, 1)})
+			assert.Nil(t, err)
+			assert.True(t, i.Contains(v), "%s should contain %s", i, v.RatString())
+		}
+	}
+}
+
+func Test_IntervalPruning(t *testing.T) {
+	p, err := NewParser("10 - b1 * 3 - b2")
+	assert.Nil(t, err)
+
+	i, err := p.EvalInterval(map[string]Interval{"b1": NewInterval(4, 6), "b2": NewInterval(0, 5)})
+	assert.Nil(t, err)
+	assert.True(t, i.Negative())
+
+	i, err = p.EvalInterval(map[string]Interval{"b1": NewInterval(0, 2), "b2": NewInterval(0, 4)})
+	assert.Nil(t, err)
+	assert.True(t, i.NonNegative())
+}
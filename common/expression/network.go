@@ -0,0 +1,286 @@
+package expression
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CycleError is returned when named expressions depend on each other in a loop
+type CycleError struct {
+	Path []string // the names around the cycle, starting and ending with the same name
+}
+
+func (e *CycleError) Error() string {
+	return "dependency cycle: " + strings.Join(e.Path, " -> ")
+}
+
+// Network is a set of named expressions that reference each other by name, evaluated in dependency order.
+// Names that are referenced but not defined are leaves whose values are given with Set.
+type Network[T any] struct {
+	backend    Backend[T]
+	exprs      map[string]*Parser[T]
+	dependents map[string][]string
+	order      []string
+	values     map[string]T
+	dirty      map[string]bool
+}
+
+func NewNetwork[T any](backend Backend[T]) *Network[T] {
+	return &Network[T]{
+		backend:    backend,
+		exprs:      make(map[string]*Parser[T]),
+		dependents: make(map[string][]string),
+		values:     make(map[string]T),
+		dirty:      make(map[string]bool),
+	}
+}
+
+// NewNetworkFromLines parses `name: expr` lines into an int64 Strict network
+func NewNetworkFromLines(lines []string) (*Network[int64], error) {
+	n := NewNetwork[int64](Int64Backend{Mode: Strict})
+	err := n.ParseLines(lines)
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// ParseLines defines an expression for each non-empty `name: expr` line
+func (n *Network[T]) ParseLines(lines []string) error {
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, expr, found := strings.Cut(line, ":")
+		if !found {
+			return errors.Errorf("line %d: expected name: expression", i+1)
+		}
+		err := n.Define(strings.TrimSpace(name), expr)
+		if err != nil {
+			return errors.Wrapf(err, "line %d", i+1)
+		}
+	}
+	return nil
+}
+
+// Define parses expr as the definition of name, replacing any previous definition or leaf value
+func (n *Network[T]) Define(name, expr string) error {
+	if !reVariable.MatchString(name) {
+		return errors.Errorf("invalid name %q", name)
+	}
+	p, err := NewBackendParser(expr, n.backend)
+	if err != nil {
+		return err
+	}
+	if old, exists := n.exprs[name]; exists {
+		for _, dep := range old.Variables() {
+			n.dependents[dep] = removeName(n.dependents[dep], name)
+		}
+	}
+	n.exprs[name] = p
+	for _, dep := range p.Variables() {
+		n.dependents[dep] = append(n.dependents[dep], name)
+	}
+	n.order = nil
+	n.invalidate(name)
+	return nil
+}
+
+func removeName(names []string, name string) []string {
+	for i, v := range names {
+		if v == name {
+			return append(names[:i:i], names[i+1:]...)
+		}
+	}
+	return names
+}
+
+// invalidate drops the cached value of name and everything that depends on it
+func (n *Network[T]) invalidate(name string) {
+	queue := []string{name}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if _, isExpr := n.exprs[cur]; isExpr {
+			if n.dirty[cur] {
+				continue
+			}
+			n.dirty[cur] = true
+			delete(n.values, cur)
+		}
+		queue = append(queue, n.dependents[cur]...)
+	}
+}
+
+// Set gives leaf name a value, so only expressions depending on it are evaluated again
+func (n *Network[T]) Set(name string, v T) error {
+	if _, isExpr := n.exprs[name]; isExpr {
+		return errors.Errorf("%s is defined by an expression", name)
+	}
+	n.values[name] = v
+	n.invalidate(name)
+	return nil
+}
+
+func (n *Network[T]) Expression(name string) *Parser[T] {
+	return n.exprs[name]
+}
+
+// Dependencies returns the names the definition of name references
+func (n *Network[T]) Dependencies(name string) []string {
+	if p, exists := n.exprs[name]; exists {
+		return p.Variables()
+	}
+	return nil
+}
+
+// Dependents returns the names of the expressions that reference name
+func (n *Network[T]) Dependents(name string) []string {
+	return append([]string(nil), n.dependents[name]...)
+}
+
+// Leaves returns the sorted names that are referenced but not defined by an expression
+func (n *Network[T]) Leaves() []string {
+	leaves := make([]string, 0, len(n.dependents))
+	for name, dependents := range n.dependents {
+		if _, isExpr := n.exprs[name]; !isExpr && len(dependents) > 0 {
+			leaves = append(leaves, name)
+		}
+	}
+	sort.Strings(leaves)
+	return leaves
+}
+
+// Order returns a copy of the defined names so every name comes after the names it depends on, or a *CycleError
+func (n *Network[T]) Order() ([]string, error) {
+	order, err := n.topologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), order...), nil
+}
+
+// topologicalOrder is Order without the copy, the cached slice Eval walks
+func (n *Network[T]) topologicalOrder() ([]string, error) {
+	if n.order != nil {
+		return n.order, nil
+	}
+
+	names := make([]string, 0, len(n.exprs))
+	for name := range n.exprs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(names))
+	order := make([]string, 0, len(names))
+	path := make([]string, 0, 8)
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		if _, isExpr := n.exprs[name]; !isExpr {
+			return nil
+		}
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, v := range path {
+				if v == name {
+					cycle := append(append([]string{}, path[i:]...), name)
+					return &CycleError{Path: cycle}
+				}
+			}
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range n.exprs[name].Variables() {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	n.order = order
+	return order, nil
+}
+
+func (n *Network[T]) evalName(name string) error {
+	v, err := n.exprs[name].Eval(n.values)
+	if err != nil {
+		return errors.Wrapf(err, "evaluating %s", name)
+	}
+	n.values[name] = v
+	delete(n.dirty, name)
+	return nil
+}
+
+// Eval evaluates every expression not already cached in topological order and returns all values
+func (n *Network[T]) Eval() (map[string]T, error) {
+	order, err := n.topologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range order {
+		if n.dirty[name] {
+			if err = n.evalName(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	values := make(map[string]T, len(n.values))
+	for name, v := range n.values {
+		values[name] = v
+	}
+	return values, nil
+}
+
+// Get returns the value of name, evaluating only the expressions it depends on that aren't cached
+func (n *Network[T]) Get(name string) (T, error) {
+	var zero T
+	if _, isExpr := n.exprs[name]; !isExpr {
+		return lookupVariable(Variable{name: name}, n.values)
+	}
+	order, err := n.topologicalOrder()
+	if err != nil {
+		return zero, err
+	}
+
+	needed := make(map[string]bool)
+	queue := []string{name}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if needed[cur] || !n.dirty[cur] {
+			continue
+		}
+		needed[cur] = true
+		queue = append(queue, n.exprs[cur].Variables()...)
+	}
+
+	for _, cur := range order {
+		if needed[cur] {
+			if err = n.evalName(cur); err != nil {
+				return zero, err
+			}
+		}
+	}
+	return n.values[name], nil
+}
@@ -0,0 +1,123 @@
+package expression
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Network(t *testing.T) {
+	n, err := NewNetworkFromLines([]string{
+		"root: pppw + sjmn",
+		"dbpl: 5",
+		"cczh: sllz + lgvd",
+		"zczc: 2",
+		"ptdq: humn - dvpt",
+		"dvpt: 3",
+		"lfqf: 4",
+		"humn: 5",
+		"ljgn: 2",
+		"sjmn: drzm * dbpl",
+		"sllz: 4",
+		"pppw: cczh / lfqf",
+		"lgvd: ljgn * ptdq",
+		"drzm: hmdt - zczc",
+		"hmdt: 32",
+	})
+	assert.Nil(t, err)
+
+	v, err := n.Get("root")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(152), v)
+
+	order, err := n.Order()
+	assert.Nil(t, err)
+	pos := make(map[string]int)
+	for i, name := range order {
+		pos[name] = i
+	}
+	for _, name := range order {
+		for _, dep := range n.Dependencies(name) {
+			assert.Less(t, pos[dep], pos[name], "%s before %s", dep, name)
+		}
+	}
+
+	// reordering the returned slice leaves the network's order alone
+	slices.Reverse(order)
+	again, err := n.Order()
+	assert.Nil(t, err)
+	assert.NotEqual(t, order, again)
+
+	values, err := n.Eval()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(150), values["sjmn"])
+	assert.Equal(t, 15, len(values))
+	assert.Empty(t, n.Leaves())
+}
+
+func Test_NetworkLeafChange(t *testing.T) {
+	n := NewNetwork[*big.Int](BigIntBackend{Mode: ExactDivision})
+	err := n.ParseLines([]string{
+		"a: x * 2",
+		"b: y + 1",
+		"c: a + b",
+		"d: b * b",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"x", "y"}, n.Leaves())
+
+	_, err = n.Get("c")
+	assert.ErrorContains(t, err, "unknown var")
+
+	assert.Nil(t, n.Set("x", big.NewInt(3)))
+	assert.Nil(t, n.Set("y", big.NewInt(4)))
+	values, err := n.Eval()
+	assert.Nil(t, err)
+	assert.Equal(t, "11", values["c"].String())
+	assert.Equal(t, "25", values["d"].String())
+	assert.Empty(t, n.dirty)
+
+	// only a and c depend on x
+	assert.Nil(t, n.Set("x", big.NewInt(10)))
+	assert.Equal(t, map[string]bool{"a": true, "c": true}, n.dirty)
+	v, err := n.Get("c")
+	assert.Nil(t, err)
+	assert.Equal(t, "25", v.String())
+	assert.Empty(t, n.dirty)
+
+	// redefining b invalidates b, c and d
+	assert.Nil(t, n.Define("b", "x - 1"))
+	assert.Equal(t, map[string]bool{"b": true, "c": true, "d": true}, n.dirty)
+	assert.Empty(t, n.Dependents("y"))
+	assert.Equal(t, []string{"x"}, n.Leaves())
+	values, err = n.Eval()
+	assert.Nil(t, err)
+	assert.Equal(t, "81", values["d"].String())
+
+	assert.NotNil(t, n.Set("a", big.NewInt(1)))
+}
+
+func Test_NetworkErrors(t *testing.T) {
+	n, err := NewNetworkFromLines([]string{
+		"a: b + 1",
+		"b: c * 2",
+		"c: a - 1",
+		"d: 4",
+	})
+	assert.Nil(t, err)
+	_, err = n.Eval()
+	var ce *CycleError
+	if assert.ErrorAs(t, err, &ce) {
+		assert.Equal(t, []string{"a", "b", "c", "a"}, ce.Path)
+	}
+	assert.EqualError(t, err, "dependency cycle: a -> b -> c -> a")
+
+	_, err = NewNetworkFromLines([]string{"a: 1", "b 2"})
+	assert.EqualError(t, err, "line 2: expected name: expression")
+
+	_, err = NewNetworkFromLines([]string{"a: 1", "b: 2 +"})
+	var pe *ParseError
+	assert.ErrorAs(t, err, &pe)
+}
@@ -9,59 +9,65 @@ import (
 // Precedence returns > 0 if op1 > op2, or < 0 if op1 < op2, otherwise 0
 type Precedence func(op1, op2 string) int
 
-type Operator struct {
-	op    string
-	left  interface{}
-	right interface{}
+// Operator is a binary operation node, its operands are Number[T], Variable or *Operator[T] nodes
+type Operator[T any] struct {
+	backend Backend[T]
+	op      string
+	left    Node
+	right   Node
 }
 
-func (o *Operator) String() string {
-	var l, r string
+func NewOperator[T any](backend Backend[T], op string, left, right Node) *Operator[T] {
+	return &Operator[T]{backend: backend, op: op, left: left, right: right}
+}
 
-	switch tl := o.left.(type) {
-	case Variable:
-		l = tl.String()
-	case int64:
-		l = fmt.Sprintf("%d", tl)
-	case *Operator:
-		l = tl.String()
-	}
+func (o *Operator[T]) Op() string {
+	return o.op
+}
 
-	switch tr := o.right.(type) {
-	case Variable:
-		r = tr.String()
-	case int64:
-		r = fmt.Sprintf("%d", tr)
-	case *Operator:
-		r = tr.String()
-	}
+func (o *Operator[T]) Left() Node {
+	return o.left
+}
+
+func (o *Operator[T]) Right() Node {
+	return o.right
+}
+
+func (o *Operator[T]) children() []Node {
+	return []Node{o.left, o.right}
+}
+
+func (o *Operator[T]) number(v T) Number[T] {
+	return Number[T]{Value: v, text: o.backend.Format(v)}
+}
 
-	return fmt.Sprintf("(%s %s %s)", l, o.op, r)
+func (o *Operator[T]) String() string {
+	return fmt.Sprintf("(%s %s %s)", o.left.String(), o.op, o.right.String())
 }
 
-func (o *Operator) InverseOperationToVariableExpression(other *Operator) (*Variable, *Operator, error) {
+func (o *Operator[T]) InverseOperationToVariableExpression(other *Operator[T]) (*Variable, *Operator[T], error) {
 
-	lVal, lIsInt := o.left.(int64)
+	lVal, lIsVal := o.left.(Number[T])
 	lVar, lIsVar := o.left.(Variable)
-	lOp, lIsOp := o.left.(*Operator)
-	rVal, rIsInt := o.right.(int64)
+	lOp, lIsOp := o.left.(*Operator[T])
+	rVal, rIsVal := o.right.(Number[T])
 	rVar, rIsVar := o.right.(Variable)
-	rOp, rIsOp := o.right.(*Operator)
+	rOp, rIsOp := o.right.(*Operator[T])
 
-	if !lIsInt && !rIsInt {
-		return nil, nil, errors.New("expected int value on one side")
+	if !lIsVal && !rIsVal {
+		return nil, nil, errors.New("expected value on one side")
 	}
 
-	if lIsInt && rIsInt {
-		return nil, nil, errors.New("didn't expect both sides as ints")
+	if lIsVal && rIsVal {
+		return nil, nil, errors.New("didn't expect both sides as values")
 	}
 
-	var value int64
+	var value Number[T]
 	var variable *Variable
-	var operator *Operator
+	var operator *Operator[T]
 	var rightInverse bool
 
-	if lIsInt {
+	if lIsVal {
 		value = lVal
 		if rIsVar {
 			variable = &rVar
@@ -79,7 +85,7 @@ func (o *Operator) InverseOperationToVariableExpression(other *Operator) (*Varia
 		rightInverse = true
 	}
 
-	newOp := &Operator{}
+	newOp := &Operator[T]{backend: o.backend}
 	newOp.left = other
 	newOp.right = value
 
@@ -90,18 +96,30 @@ func (o *Operator) InverseOperationToVariableExpression(other *Operator) (*Varia
 		if rightInverse {
 			newOp.op = "+"
 		} else {
+			minusOne, err := o.backend.Parse("-1")
+			if err != nil {
+				return nil, nil, err
+			}
 			newOp.op = "-"
-			newOp = &Operator{left: newOp, op: "/", right: int64(-1)}
+			newOp = &Operator[T]{backend: o.backend, left: newOp, op: "/", right: o.number(minusOne)}
 		}
 	case "*":
 		newOp.op = "/"
+	case "XOR":
+		newOp.op = "XOR"
 	case "/":
 		if rightInverse {
 			newOp.op = "*"
 		} else {
+			one, err := o.backend.Parse("1")
+			if err != nil {
+				return nil, nil, err
+			}
 			newOp.op = "/"
-			newOp = &Operator{left: int64(1), op: "/", right: newOp}
+			newOp = &Operator[T]{backend: o.backend, left: o.number(one), op: "/", right: newOp}
 		}
+	default:
+		return nil, nil, errors.Errorf("can't invert operator %s", o.op)
 	}
 
 	if variable != nil {
@@ -111,103 +129,96 @@ func (o *Operator) InverseOperationToVariableExpression(other *Operator) (*Varia
 	}
 }
 
-func (o *Operator) EvalKnown(vars map[string]int64) (int64, error) {
-	var l, r int64
-	var el, er error
-	switch tl := o.left.(type) {
-	case Variable:
-		l, el = tl.EvalKnown(vars)
-		if el == nil {
-			o.left = l
-		}
-	case int64:
-		l = tl
-	case *Operator:
-		l, el = tl.EvalKnown(vars)
-		if el == nil {
-			o.left = l
-		}
-	}
-	switch tr := o.right.(type) {
-	case Variable:
-		r, er = tr.EvalKnown(vars)
-		if er == nil {
-			o.right = r
-		}
-	case int64:
-		r = tr
-	case *Operator:
-		r, er = tr.EvalKnown(vars)
-		if er == nil {
-			o.right = r
-		}
-	}
-
-	if el != nil || er != nil {
-		return 0, errors.New("unable to eval operator")
-	}
-
-	switch o.op {
+func applyOperator[T any](backend Backend[T], op string, l, r T) (T, error) {
+	switch op {
 	case "-":
-		return l - r, nil
+		return backend.Sub(l, r)
 	case "+":
-		return l + r, nil
+		return backend.Add(l, r)
 	case "*":
-		return l * r, nil
+		return backend.Mul(l, r)
 	case "/":
-		if l%r != 0 {
-			return 0, errors.New("not divisible")
-		}
-		return l / r, nil
+		return backend.Div(l, r)
+	case "|":
+		return backend.Concat(l, r)
 	}
-	panic(errors.New("unknown operator"))
+	return applyBitwiseOperator(backend, op, l, r)
+}
+
+func (o *Operator[T]) apply(l, r T) (T, error) {
+	return applyOperator(o.backend, o.op, l, r)
 }
 
-func (o *Operator) Eval(vars map[string]int64) (int64, error) {
-	var e error
-	var l, r int64
-	switch tl := o.left.(type) {
+// evalKnownOperand evaluates operand, returning the node it folds into
+func (o *Operator[T]) evalKnownOperand(operand Node, vars map[string]T) (T, Node, error) {
+	var zero T
+	switch t := operand.(type) {
 	case Variable:
-		l, e = tl.Eval(vars)
-		if e != nil {
-			return 0, e
+		v, err := lookupVariable(t, vars)
+		if err != nil {
+			return zero, operand, err
 		}
-	case int64:
-		l = tl
-	case *Operator:
-		l, e = tl.Eval(vars)
-		if e != nil {
-			return 0, e
+		return v, o.number(v), nil
+	case *Operator[T]:
+		v, err := t.EvalKnown(vars)
+		if err != nil {
+			return zero, operand, err
 		}
+		return v, o.number(v), nil
+	case Number[T]:
+		return t.Value, operand, nil
 	}
-	switch tr := o.right.(type) {
+	return zero, operand, ErrInvalidOperands
+}
+
+// EvalKnown evaluates the operator, folding every subtree whose variables are all known into a value
+func (o *Operator[T]) EvalKnown(vars map[string]T) (T, error) {
+	var zero T
+	l, left, el := o.evalKnownOperand(o.left, vars)
+	o.left = left
+	r, right, er := o.evalKnownOperand(o.right, vars)
+	o.right = right
+
+	if el != nil || er != nil {
+		return zero, errors.New("unable to eval operator")
+	}
+
+	return o.apply(l, r)
+}
+
+// evalOperand evaluates a T operand tree with the arithmetic of backend, converting each literal with conv
+func evalOperand[T, U any](operand Node, backend Backend[U], conv func(T) (U, error), vars map[string]U) (U, error) {
+	var zero U
+	switch t := operand.(type) {
 	case Variable:
-		r, e = tr.Eval(vars)
+		return lookupVariable(t, vars)
+	case *Operator[T]:
+		l, e := evalOperand(t.left, backend, conv, vars)
 		if e != nil {
-			return 0, e
+			return zero, e
 		}
-	case int64:
-		r = tr
-	case *Operator:
-		r, e = tr.Eval(vars)
+		r, e := evalOperand(t.right, backend, conv, vars)
 		if e != nil {
-			return 0, e
+			return zero, e
 		}
+		return applyOperator(backend, t.op, l, r)
+	case Number[T]:
+		return conv(t.Value)
 	}
-	switch o.op {
-	case "-":
-		return l - r, nil
-	case "+":
-		return l + r, nil
-	case "*":
-		return l * r, nil
-	case "/":
-		if l%r != 0 {
-			return 0, errors.New("not divisible")
-		}
-		return l / r, nil
-	}
-	panic(errors.New("unknown operator"))
+	return zero, ErrInvalidOperands
+}
+
+func identity[T any](v T) (T, error) {
+	return v, nil
+}
+
+func (o *Operator[T]) Eval(vars map[string]T) (T, error) {
+	return o.EvalWith(o.backend, vars)
+}
+
+// EvalWith evaluates the operator using the arithmetic of backend instead of the one it was parsed with
+func (o *Operator[T]) EvalWith(backend Backend[T], vars map[string]T) (T, error) {
+	return evalOperand(o, backend, identity[T], vars)
 }
 
 func CompareOperator(op1, op2 string) int {
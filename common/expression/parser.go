@@ -1,38 +1,43 @@
 package expression
 
 import (
+	"fmt"
+	"math/big"
 	"regexp"
-	"strconv"
-
-	"github.com/pkg/errors"
+	"unicode/utf8"
 )
 
 // https://www.engr.mun.ca/~theo/Misc/exp_parsing.htm
 
 var (
 	reSpace       = regexp.MustCompile(`\s`)
-	reOperator    = regexp.MustCompile(`\+|\*|\-|\/`)
-	reDigitChar   = regexp.MustCompile(`^(\-|\d)$`)
-	reDigits      = regexp.MustCompile(`^-?\d+$`)
-	reVariable    = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9]*$`)
+	reOperator    = regexp.MustCompile(`^(\+|\*|\-|\/|\||&|\^|~)$`)
+	reShift       = regexp.MustCompile(`^(<<|>>)`)
+	reDigitChar   = regexp.MustCompile(`^\d$`)
+	reDigits      = regexp.MustCompile(`^\d+(\.\d*)?$`)
+	reVariable    = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
 	precedenceMap = map[string]int{
 		"*": 10,
 		"/": 10,
 		"+": 5,
 		"-": 5,
+		"|": 1,
 	}
 )
 
-type Parser struct {
-	operators    []*Operator
-	operands     []interface{}
+// Parser parses an infix expression into a tree evaluated with the arithmetic of its Backend
+type Parser[T any] struct {
+	backend      Backend[T]
+	operators    []*Operator[T]
+	operands     []Node
 	opPrecedence Precedence
+	bitwise      bool
 	start        int
 	end          int
 	expr         string
 }
 
-func (p *Parser) E() error {
+func (p *Parser[T]) E() error {
 	err := p.P()
 	if err != nil {
 		return err
@@ -41,8 +46,8 @@ func (p *Parser) E() error {
 	if err != nil {
 		return err
 	}
-	for reOperator.MatchString(n) && IsBinary(n) {
-		p.pushOperator(n)
+	for op, ok := p.binaryOperator(n); ok; op, ok = p.binaryOperator(n) {
+		p.pushOperator(op)
 		p.consume()
 
 		err = p.P()
@@ -61,18 +66,70 @@ func (p *Parser) E() error {
 	return nil
 }
 
-func (p *Parser) P() error {
+func (p *Parser[T]) P() error {
 	n, err := p.next()
 	if err != nil {
 		return err
 	}
 	if reDigits.MatchString(n) {
-		v, _ := strconv.ParseInt(n, 10, 64)
+		v, err := p.number(n, p.start)
+		if err != nil {
+			return err
+		}
 		p.operands = append(p.operands, v)
 		p.consume()
-	} else if reVariable.MatchString(n) {
+	} else if p.bitwise && isUnaryNot(n) {
+		// NOT x is stored as x XOR all ones
+		p.consume()
+		zero, err := p.number("0", p.start)
+		if err != nil {
+			return err
+		}
+		mask, err := p.backend.(BitwiseBackend[T]).Not(zero.Value)
+		if err != nil {
+			return err
+		}
+		err = p.P()
+		if err != nil {
+			return err
+		}
+		o := &Operator[T]{backend: p.backend, op: "XOR"}
+		o.left = p.operands[len(p.operands)-1]
+		o.right = Number[T]{Value: mask, text: p.backend.Format(mask)}
+		p.operands[len(p.operands)-1] = o
+	} else if reVariable.MatchString(n) && !p.reserved(n) {
 		p.operands = append(p.operands, Variable{name: n})
 		p.consume()
+	} else if n == "-" {
+		offset := p.start
+		p.consume()
+		n, err = p.next()
+		if err != nil {
+			return err
+		}
+		if reDigits.MatchString(n) {
+			v, err := p.number("-"+n, offset)
+			if err != nil {
+				return err
+			}
+			p.operands = append(p.operands, v)
+			p.consume()
+		} else {
+			// unary minus on anything other than a literal is 0 - operand
+			zero, err := p.number("0", offset)
+			if err != nil {
+				return err
+			}
+			p.operands = append(p.operands, zero)
+			err = p.P()
+			if err != nil {
+				return err
+			}
+			o := &Operator[T]{backend: p.backend, op: "-"}
+			o.right = p.operands[len(p.operands)-1]
+			o.left = p.operands[len(p.operands)-2]
+			p.operands = append(p.operands[0:len(p.operands)-2], o)
+		}
 	} else if n == "(" {
 		p.consume()
 		p.operators = append(p.operators, nil)
@@ -85,17 +142,52 @@ func (p *Parser) P() error {
 			return err
 		}
 		if n != ")" {
-			return errors.New("expected )")
+			return p.errorf("expected )")
 		}
 		p.consume()
 		p.operators = p.operators[0 : len(p.operators)-1]
 	} else {
-		return errors.New("error parsing expression")
+		return p.errorf("expected a number, variable or (")
 	}
 	return nil
 }
 
-func (p *Parser) popOperator() {
+// binaryOperator returns the operator token n stands for in the parser's grammar
+func (p *Parser[T]) binaryOperator(n string) (string, bool) {
+	if p.bitwise {
+		if op, ok := bitwiseOperators[n]; ok {
+			return op, true
+		}
+	}
+	if _, ok := precedenceMap[n]; ok && IsBinary(n) {
+		return n, true
+	}
+	return "", false
+}
+
+// reserved returns true for the words of the bitwise grammar, which can't be variable names
+func (p *Parser[T]) reserved(n string) bool {
+	if !p.bitwise {
+		return false
+	}
+	_, isOp := bitwiseOperators[n]
+	return isOp || isUnaryNot(n)
+}
+
+func (p *Parser[T]) number(n string, offset int) (Number[T], error) {
+	v, err := p.backend.Parse(n)
+	if err != nil {
+		return Number[T]{}, newParseError(p.expr, offset, n, err, "invalid number")
+	}
+	return Number[T]{Value: v, text: n}, nil
+}
+
+// errorf returns a ParseError at the current token
+func (p *Parser[T]) errorf(format string, args ...interface{}) error {
+	return newParseError(p.expr, p.start, p.expr[p.start:p.end], nil, fmt.Sprintf(format, args...))
+}
+
+func (p *Parser[T]) popOperator() {
 	op := p.operators[len(p.operators)-1]
 	if IsBinary(op.op) {
 		p.operators = p.operators[0 : len(p.operators)-1]
@@ -106,36 +198,36 @@ func (p *Parser) popOperator() {
 	}
 }
 
-func (p *Parser) pushOperator(op string) {
+func (p *Parser[T]) pushOperator(op string) {
 	for p.operators[len(p.operators)-1] != nil && p.opPrecedence(op, p.operators[len(p.operators)-1].op) <= 0 {
 		p.popOperator()
 	}
-	o := Operator{}
+	o := Operator[T]{backend: p.backend}
 	o.op = op
 	p.operators = append(p.operators, &o)
 }
 
-func (p *Parser) next() (string, error) {
+func (p *Parser[T]) next() (string, error) {
 
 	for p.start < len(p.expr) && reSpace.MatchString(string(p.expr[p.start])) {
 		p.start++
 	}
 
 	if p.start == len(p.expr) {
+		p.end = p.start
 		return "", nil
 	}
 
 	p.end = p.start
 
 	if reDigitChar.MatchString(string(p.expr[p.start])) {
-		if string(p.expr[p.start]) == "-" {
-			p.end++
-		}
 		for p.end < len(p.expr) && reDigits.MatchString(string(p.expr[p.start:p.end+1])) {
 			p.end++
 		}
 	} else if p.expr[p.start] == '(' || p.expr[p.start] == ')' {
 		p.end++
+	} else if reShift.MatchString(p.expr[p.start:]) {
+		p.end += 2
 	} else if reOperator.MatchString(string(p.expr[p.start])) {
 		p.end++
 	} else if reVariable.MatchString(string(p.expr[p.start])) {
@@ -143,67 +235,165 @@ func (p *Parser) next() (string, error) {
 			p.end++
 		}
 	} else {
-		return "", errors.New("unexpected token")
+		_, size := utf8.DecodeRuneInString(p.expr[p.start:])
+		p.end = p.start + size
+		return "", p.errorf("unexpected character")
 	}
 
 	return p.expr[p.start:p.end], nil
 }
 
-func (p *Parser) consume() {
+func (p *Parser[T]) consume() {
 	p.start = p.end
 }
 
-func (p *Parser) Eval(vars map[string]int64) (int64, error) {
-	if len(p.operands) == 1 {
-		switch v := p.operands[0].(type) {
-		case int64:
-			return v, nil
-		case *Operator:
-			return v.Eval(vars)
+func (p *Parser[T]) Eval(vars map[string]T) (T, error) {
+	return p.EvalWith(p.backend, vars)
+}
+
+// EvalWith evaluates the expression using the arithmetic of backend instead of the one it was parsed with,
+// e.g. Int64Backend{Mode: CheckedOverflow} to catch overflow without rejecting inexact division
+func (p *Parser[T]) EvalWith(backend Backend[T], vars map[string]T) (T, error) {
+	if len(p.operands) != 1 {
+		var zero T
+		return zero, ErrInvalidOperands
+	}
+	return evalOperand(p.operands[0], backend, identity[T], vars)
+}
+
+// EvalAs evaluates p with the arithmetic of backend, converting p's literals and vars to U through their decimal form
+func EvalAs[T, U any](p *Parser[T], backend Backend[U], vars map[string]T) (U, error) {
+	var zero U
+	if len(p.operands) != 1 {
+		return zero, ErrInvalidOperands
+	}
+	conv := func(v T) (U, error) {
+		return backend.Parse(p.backend.Format(v))
+	}
+	converted := make(map[string]U, len(vars))
+	for name, v := range vars {
+		c, err := conv(v)
+		if err != nil {
+			return zero, err
 		}
+		converted[name] = c
 	}
-	panic("invalid operands")
+	return evalOperand(p.operands[0], backend, conv, converted)
+}
+
+// EvalRat evaluates the expression exactly in big.Rat, no division truncates and nothing overflows
+func (p *Parser[T]) EvalRat(vars map[string]T) (*big.Rat, error) {
+	return EvalAs[T, *big.Rat](p, BigRatBackend{}, vars)
 }
 
-func (p *Parser) EvalKnown(vars map[string]int64) (int64, error) {
+// EvalExact evaluates the expression exactly in big.Rat and converts the result back to T,
+// returning ErrNotInteger if the result isn't a whole number, or ErrOverflow if it doesn't fit in T
+func (p *Parser[T]) EvalExact(vars map[string]T) (T, error) {
+	var zero T
+	r, err := p.EvalRat(vars)
+	if err != nil {
+		return zero, err
+	}
+	if !r.IsInt() {
+		return zero, ErrNotInteger
+	}
+	v, err := p.backend.Parse(r.Num().String())
+	if err != nil {
+		return zero, ErrOverflow
+	}
+	return v, nil
+}
+
+func (p *Parser[T]) EvalKnown(vars map[string]T) (T, error) {
+	var zero T
 	if len(p.operands) == 1 {
 		switch v := p.operands[0].(type) {
-		case int64:
-			return v, nil
-		case *Operator:
+		case *Operator[T]:
 			return v.EvalKnown(vars)
+		case Variable:
+			val, err := lookupVariable(v, vars)
+			if err == nil {
+				p.operands[0] = Number[T]{Value: val, text: p.backend.Format(val)}
+			}
+			return val, err
+		case Number[T]:
+			return v.Value, nil
 		}
 	}
-	panic("invalid operands")
+	return zero, ErrInvalidOperands
 }
 
-func (p *Parser) RootOperator() *Operator {
-	return p.operands[0].(*Operator)
+func (p *Parser[T]) Backend() Backend[T] {
+	return p.backend
 }
 
-func (p *Parser) String() string {
-	return p.operands[0].(*Operator).String()
+// Root returns the root node of the parsed expression tree
+func (p *Parser[T]) Root() Node {
+	return p.operands[0]
 }
 
-func NewParser(expr string) (*Parser, error) {
-	p := func(op1, op2 string) int {
-		if precedenceMap[op1] > precedenceMap[op2] {
-			return 1
-		}
-		if precedenceMap[op1] < precedenceMap[op2] {
-			return -1
-		}
-		return 0
+// RootOperator returns the root node if it is an operator, otherwise nil
+func (p *Parser[T]) RootOperator() *Operator[T] {
+	o, _ := p.operands[0].(*Operator[T])
+	return o
+}
+
+// Variables returns the distinct variable names referenced by the expression in the order first seen
+func (p *Parser[T]) Variables() []string {
+	return Variables(p.Root())
+}
+
+func (p *Parser[T]) String() string {
+	return p.operands[0].String()
+}
+
+func defaultPrecedence(op1, op2 string) int {
+	if precedenceMap[op1] > precedenceMap[op2] {
+		return 1
 	}
-	return NewParserWithPrecedence(expr, p)
+	if precedenceMap[op1] < precedenceMap[op2] {
+		return -1
+	}
+	return 0
+}
+
+// NewParser parses expr for evaluation with int64 values in Strict mode
+func NewParser(expr string) (*Parser[int64], error) {
+	return NewBackendParser[int64](expr, Int64Backend{Mode: Strict})
+}
+
+func NewParserWithPrecedence(expr string, precedence Precedence) (*Parser[int64], error) {
+	return NewBackendParserWithPrecedence[int64](expr, Int64Backend{Mode: Strict}, precedence)
+}
+
+func NewBigIntParser(expr string) (*Parser[*big.Int], error) {
+	return NewBackendParser[*big.Int](expr, BigIntBackend{Mode: ExactDivision})
+}
+
+func NewBigRatParser(expr string) (*Parser[*big.Rat], error) {
+	return NewBackendParser[*big.Rat](expr, BigRatBackend{})
+}
+
+func NewFloat64Parser(expr string) (*Parser[float64], error) {
+	return NewBackendParser[float64](expr, Float64Backend{})
 }
 
-func NewParserWithPrecedence(expr string, precedence Precedence) (*Parser, error) {
-	p := Parser{}
+func NewBackendParser[T any](expr string, backend Backend[T]) (*Parser[T], error) {
+	return NewBackendParserWithPrecedence(expr, backend, defaultPrecedence)
+}
+
+func NewBackendParserWithPrecedence[T any](expr string, backend Backend[T], precedence Precedence) (*Parser[T], error) {
+	return newParser(expr, backend, precedence, false)
+}
+
+func newParser[T any](expr string, backend Backend[T], precedence Precedence, bitwise bool) (*Parser[T], error) {
+	p := Parser[T]{}
+	p.backend = backend
 	p.opPrecedence = precedence
+	p.bitwise = bitwise
 	p.expr = expr
-	p.operators = make([]*Operator, 0, 20)
-	p.operands = make([]interface{}, 0, 20)
+	p.operators = make([]*Operator[T], 0, 20)
+	p.operands = make([]Node, 0, 20)
 
 	p.operators = append(p.operators, nil)
 	err := p.E()
@@ -211,5 +401,13 @@ func NewParserWithPrecedence(expr string, precedence Precedence) (*Parser, error
 		return nil, err
 	}
 
+	n, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if n != "" {
+		return nil, p.errorf("unexpected token")
+	}
+
 	return &p, nil
 }
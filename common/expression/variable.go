@@ -2,24 +2,31 @@ package expression
 
 import "github.com/pkg/errors"
 
+// Variable is a named value node
 type Variable struct {
 	name string
 }
 
-func (v Variable) Eval(vars map[string]int64) (int64, error) {
-	if val, exists := vars[v.name]; exists {
-		return val, nil
-	}
-	return 0, errors.New("variable not found")
+func NewVariable(name string) Variable {
+	return Variable{name: name}
 }
 
-func (v Variable) EvalKnown(vars map[string]int64) (int64, error) {
+func lookupVariable[T any](v Variable, vars map[string]T) (T, error) {
 	if val, exists := vars[v.name]; exists {
 		return val, nil
 	}
-	return 0, errors.Errorf("unknown var %s", v.name)
+	var zero T
+	return zero, errors.Errorf("unknown var %s", v.name)
+}
+
+func (v Variable) Name() string {
+	return v.name
 }
 
 func (v Variable) String() string {
 	return v.name
 }
+
+func (v Variable) children() []Node {
+	return nil
+}
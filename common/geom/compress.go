@@ -0,0 +1,189 @@
+package geom
+
+import (
+	"sort"
+
+	"github.com/mbordner/aoc2025/common"
+	"github.com/pkg/errors"
+)
+
+// Compression maps the values of one axis onto a compact run of cells between sorted breakpoints, cell i covering
+// [Start(i), Start(i) + Width(i)). The cells keep their widths, so lengths, areas and volumes summed over cells are
+// exact.
+type Compression[T IntNumber] struct {
+	breaks []T
+}
+
+// NewCompression makes a cell between each pair of consecutive breakpoints, the last one only ends a cell
+func NewCompression[T IntNumber](breakpoints ...T) *Compression[T] {
+	seen := make(map[T]bool, len(breakpoints))
+	breaks := make([]T, 0, len(breakpoints))
+	for _, b := range breakpoints {
+		if !seen[b] {
+			seen[b] = true
+			breaks = append(breaks, b)
+		}
+	}
+	sort.Slice(breaks, func(i, j int) bool { return breaks[i] < breaks[j] })
+	return &Compression[T]{breaks: breaks}
+}
+
+// NewTileCompression gives each of the tile values a cell of width 1, with the runs of tiles between them as wider
+// cells, for closed integer coordinates like the corners of a grid polygon
+func NewTileCompression[T IntNumber](values ...T) *Compression[T] {
+	breakpoints := make([]T, 0, 2*len(values))
+	for _, v := range values {
+		breakpoints = append(breakpoints, v, v+1)
+	}
+	return NewCompression(breakpoints...)
+}
+
+// Len returns how many cells there are
+func (c *Compression[T]) Len() int {
+	return Max(len(c.breaks)-1, 0)
+}
+
+func (c *Compression[T]) Breakpoints() []T {
+	return c.breaks
+}
+
+// Breakpoint returns the index of breakpoint b, which is also the index of the cell it starts
+func (c *Compression[T]) Breakpoint(b T) (int, bool) {
+	i := sort.Search(len(c.breaks), func(i int) bool { return c.breaks[i] >= b })
+	return i, i < len(c.breaks) && c.breaks[i] == b
+}
+
+// Index returns the cell containing v
+func (c *Compression[T]) Index(v T) (int, bool) {
+	i := sort.Search(len(c.breaks), func(i int) bool { return c.breaks[i] > v }) - 1
+	return i, i >= 0 && i < c.Len()
+}
+
+func (c *Compression[T]) Start(i int) T {
+	return c.breaks[i]
+}
+
+func (c *Compression[T]) Width(i int) T {
+	return c.breaks[i+1] - c.breaks[i]
+}
+
+// Length returns the total width of cells i to j
+func (c *Compression[T]) Length(i, j int) T {
+	return c.breaks[j+1] - c.breaks[i]
+}
+
+// cells returns the cells covering the closed range from lo to hi, which has to start and end on cell boundaries
+func (c *Compression[T]) cells(lo, hi T) (int, int, error) {
+	i, ok := c.Breakpoint(lo)
+	j, ok2 := c.Breakpoint(hi + 1)
+	if !ok || !ok2 || j == 0 {
+		return 0, 0, errors.Errorf("range %d to %d isn't made of whole cells", lo, hi)
+	}
+	return i, j - 1, nil
+}
+
+// CompressedGrid is a common.Grid with a cell per X and Y compression cell, Grid[y][x] standing for all the tiles of
+// the cell
+type CompressedGrid struct {
+	X, Y *Compression[int]
+	Grid common.Grid
+}
+
+func NewCompressedGrid(xs, ys *Compression[int], fill byte) *CompressedGrid {
+	grid := make(common.Grid, ys.Len())
+	for y := range grid {
+		grid[y] = make([]byte, xs.Len())
+		for x := range grid[y] {
+			grid[y][x] = fill
+		}
+	}
+	return &CompressedGrid{X: xs, Y: ys, Grid: grid}
+}
+
+// Pos returns the grid position of the cell containing x, y
+func (cg *CompressedGrid) Pos(x, y int) (common.Pos, bool) {
+	cx, okx := cg.X.Index(x)
+	cy, oky := cg.Y.Index(y)
+	return common.Pos{X: cx, Y: cy}, okx && oky
+}
+
+// Fill sets the cells of the closed rectangle from x0, y0 to x1, y1, which has to be made of whole cells
+func (cg *CompressedGrid) Fill(x0, y0, x1, y1 int, v byte) error {
+	cx0, cx1, err := cg.X.cells(Min(x0, x1), Max(x0, x1))
+	if err != nil {
+		return err
+	}
+	cy0, cy1, err := cg.Y.cells(Min(y0, y1), Max(y0, y1))
+	if err != nil {
+		return err
+	}
+	for y := cy0; y <= cy1; y++ {
+		for x := cx0; x <= cx1; x++ {
+			cg.Grid[y][x] = v
+		}
+	}
+	return nil
+}
+
+// CellArea returns how many tiles the cell at p stands for
+func (cg *CompressedGrid) CellArea(p common.Pos) int {
+	return cg.X.Width(p.X) * cg.Y.Width(p.Y)
+}
+
+// Area returns how many tiles are set to v
+func (cg *CompressedGrid) Area(v byte) int {
+	area := 0
+	for y, row := range cg.Grid {
+		for x, b := range row {
+			if b == v {
+				area += cg.CellArea(common.Pos{X: x, Y: y})
+			}
+		}
+	}
+	return area
+}
+
+// BoxCompression compresses each axis of a set of closed boxes, so every box is a whole box of cells
+type BoxCompression[T IntNumber] struct {
+	X, Y, Z *Compression[T]
+}
+
+func NewBoxCompression[T IntNumber](boxes ...BoundingBox[T]) *BoxCompression[T] {
+	var xs, ys, zs []T
+	for _, bb := range boxes {
+		xs = append(xs, bb.MinX, bb.MaxX+1)
+		ys = append(ys, bb.MinY, bb.MaxY+1)
+		zs = append(zs, bb.MinZ, bb.MaxZ+1)
+	}
+	return &BoxCompression[T]{X: NewCompression(xs...), Y: NewCompression(ys...), Z: NewCompression(zs...)}
+}
+
+// Cells returns the box of cell indexes making up bb, which has to be made of whole cells
+func (bc *BoxCompression[T]) Cells(bb BoundingBox[T]) (BoundingBox[int], error) {
+	var cells BoundingBox[int]
+	var err error
+	if cells.MinX, cells.MaxX, err = bc.X.cells(bb.MinX, bb.MaxX); err != nil {
+		return cells, errors.Wrap(err, "x")
+	}
+	if cells.MinY, cells.MaxY, err = bc.Y.cells(bb.MinY, bb.MaxY); err != nil {
+		return cells, errors.Wrap(err, "y")
+	}
+	if cells.MinZ, cells.MaxZ, err = bc.Z.cells(bb.MinZ, bb.MaxZ); err != nil {
+		return cells, errors.Wrap(err, "z")
+	}
+	return cells, nil
+}
+
+// Box returns the closed box of values a box of cells covers
+func (bc *BoxCompression[T]) Box(cells BoundingBox[int]) BoundingBox[T] {
+	return BoundingBox[T]{
+		MinX: bc.X.Start(cells.MinX), MaxX: bc.X.Start(cells.MaxX+1) - 1,
+		MinY: bc.Y.Start(cells.MinY), MaxY: bc.Y.Start(cells.MaxY+1) - 1,
+		MinZ: bc.Z.Start(cells.MinZ), MaxZ: bc.Z.Start(cells.MaxZ+1) - 1,
+	}
+}
+
+// Volume returns how many values a box of cells covers
+func (bc *BoxCompression[T]) Volume(cells BoundingBox[int]) T {
+	return bc.X.Length(cells.MinX, cells.MaxX) * bc.Y.Length(cells.MinY, cells.MaxY) * bc.Z.Length(cells.MinZ, cells.MaxZ)
+}
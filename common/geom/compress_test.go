@@ -0,0 +1,134 @@
+package geom
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/mbordner/aoc2025/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Compression(t *testing.T) {
+	c := NewCompression(10, 3, 7, 3)
+	assert.Equal(t, []int{3, 7, 10}, c.Breakpoints())
+	assert.Equal(t, 2, c.Len())
+
+	cases := []struct {
+		v     int
+		index int
+		found bool
+	}{
+		{v: 2, found: false},
+		{v: 3, index: 0, found: true},
+		{v: 6, index: 0, found: true},
+		{v: 7, index: 1, found: true},
+		{v: 9, index: 1, found: true},
+		{v: 10, found: false},
+	}
+	for _, tc := range cases {
+		i, found := c.Index(tc.v)
+		assert.Equal(t, tc.found, found, "%d", tc.v)
+		if tc.found {
+			assert.Equal(t, tc.index, i, "%d", tc.v)
+		}
+	}
+
+	assert.Equal(t, 4, c.Width(0))
+	assert.Equal(t, 3, c.Width(1))
+	assert.Equal(t, 7, c.Length(0, 1))
+	i, found := c.Breakpoint(7)
+	assert.True(t, found)
+	assert.Equal(t, 1, i)
+	_, found = c.Breakpoint(8)
+	assert.False(t, found)
+
+	tiles := NewTileCompression(5, 1, 2)
+	assert.Equal(t, []int{1, 2, 3, 5, 6}, tiles.Breakpoints())
+	assert.Equal(t, 0, NewCompression[int]().Len())
+}
+
+func Test_CompressedGrid(t *testing.T) {
+	p, _ := NewPolygon(theater)
+	xs := make([]int, len(theater))
+	ys := make([]int, len(theater))
+	for i, v := range theater {
+		xs[i], ys[i] = v.X, v.Y
+	}
+	cg := NewCompressedGrid(NewTileCompression(xs...), NewTileCompression(ys...), '.')
+	assert.Less(t, cg.X.Len()*cg.Y.Len(), (p.BoundingBox().MaxX-p.BoundingBox().MinX+1)*(p.BoundingBox().MaxY-p.BoundingBox().MinY+1))
+
+	for i := range theater {
+		a, b := theater[i], theater[(i+1)%len(theater)]
+		assert.NoError(t, cg.Fill(a.X, a.Y, b.X, b.Y, '#'))
+	}
+	assert.Equal(t, p.BoundaryPoints(), cg.Area('#'))
+
+	// the tiles of a cell are all inside or all outside, so its first one decides
+	for y, row := range cg.Grid {
+		for x := range row {
+			if row[x] == '.' && p.Contains(Pos[int]{X: cg.X.Start(x), Y: cg.Y.Start(y)}) {
+				row[x] = 'X'
+			}
+		}
+	}
+	assert.Equal(t, p.InteriorPoints(), cg.Area('X'))
+	assert.Equal(t, p.LatticePoints(), cg.Area('#')+cg.Area('X'))
+
+	pos, found := cg.Pos(10, 4)
+	assert.True(t, found)
+	assert.Equal(t, common.Pos{X: cg.X.Len() - 2, Y: 3}, pos)
+	assert.Equal(t, byte('X'), cg.Grid[pos.Y][pos.X])
+	_, found = cg.Pos(12, 4)
+	assert.False(t, found)
+
+	assert.Error(t, cg.Fill(4, 1, 7, 1, '#'))
+}
+
+func Test_BoxCompression(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	boxes := make([]BoundingBox[int], 12)
+	for i := range boxes {
+		var bb BoundingBox[int]
+		x, y, z := r.Intn(20)-10, r.Intn(20)-10, r.Intn(20)-10
+		bb.SetExtents(x, y, z, x+r.Intn(8), y+r.Intn(8), z+r.Intn(8))
+		boxes[i] = bb
+	}
+	bc := NewBoxCompression(boxes...)
+
+	filled := make(map[Pos[int]]bool)
+	for _, bb := range boxes {
+		cells, err := bc.Cells(bb)
+		assert.NoError(t, err)
+		assert.Equal(t, bb, bc.Box(cells))
+		assert.Equal(t, (bb.MaxX-bb.MinX+1)*(bb.MaxY-bb.MinY+1)*(bb.MaxZ-bb.MinZ+1), bc.Volume(cells))
+		for x := cells.MinX; x <= cells.MaxX; x++ {
+			for y := cells.MinY; y <= cells.MaxY; y++ {
+				for z := cells.MinZ; z <= cells.MaxZ; z++ {
+					filled[Pos[int]{X: x, Y: y, Z: z}] = true
+				}
+			}
+		}
+	}
+	union := 0
+	for c := range filled {
+		union += bc.Volume(BoundingBox[int]{MinX: c.X, MaxX: c.X, MinY: c.Y, MaxY: c.Y, MinZ: c.Z, MaxZ: c.Z})
+	}
+
+	expected := 0
+	for x := -10; x < 20; x++ {
+		for y := -10; y < 20; y++ {
+			for z := -10; z < 20; z++ {
+				for _, bb := range boxes {
+					if bb.Contains(Pos[int]{X: x, Y: y, Z: z}) {
+						expected++
+						break
+					}
+				}
+			}
+		}
+	}
+	assert.Equal(t, expected, union)
+
+	_, err := bc.Cells(BoundingBox[int]{MinX: -100, MaxX: 100})
+	assert.Error(t, err)
+}
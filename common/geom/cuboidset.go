@@ -0,0 +1,81 @@
+package geom
+
+// CuboidSet is a union of cuboids built by adding and removing cuboids in order, like reboot steps turning cubes on
+// and off. It keeps signed cuboids whose weights sum to 1 at every point in the set and 0 elsewhere, so a new step
+// only cancels its overlap with each signed cuboid, rather than splitting cuboids against each other like
+// Cuboids.Merge and Cuboids.Remove do.
+type CuboidSet struct {
+	weights map[Cuboid]int64
+}
+
+func NewCuboidSet(cs ...Cuboid) *CuboidSet {
+	s := &CuboidSet{weights: make(map[Cuboid]int64)}
+	for _, c := range cs {
+		s.Add(c)
+	}
+	return s
+}
+
+// intersection returns the cuboid both c and o cover, if any
+func (c Cuboid) intersection(o Cuboid) (Cuboid, bool) {
+	n := Cuboid{Min: c.Min.Max(o.Min), Max: c.Max.Min(o.Max)}
+	return n, n.Min.X <= n.Max.X && n.Min.Y <= n.Max.Y && n.Min.Z <= n.Max.Z
+}
+
+func (s *CuboidSet) step(c Cuboid, on bool) {
+	overlaps := make(map[Cuboid]int64)
+	for o, w := range s.weights {
+		if n, ok := o.intersection(c); ok {
+			overlaps[n] -= w
+		}
+	}
+	if on {
+		overlaps[c]++
+	}
+	for n, w := range overlaps {
+		if s.weights[n] += w; s.weights[n] == 0 {
+			delete(s.weights, n)
+		}
+	}
+}
+
+func (s *CuboidSet) Add(c Cuboid) {
+	s.step(c, true)
+}
+
+func (s *CuboidSet) Remove(c Cuboid) {
+	s.step(c, false)
+}
+
+// Len returns how many signed cuboids the set keeps
+func (s *CuboidSet) Len() int {
+	return len(s.weights)
+}
+
+func (s *CuboidSet) Contains(p Point) bool {
+	sum := int64(0)
+	for c, w := range s.weights {
+		if c.Contains(p) {
+			sum += w
+		}
+	}
+	return sum > 0
+}
+
+// PointsCount returns how many integer points are in the set
+func (s *CuboidSet) PointsCount() uint64 {
+	sum := int64(0)
+	for c, w := range s.weights {
+		sum += w * int64(c.PointsCount())
+	}
+	return uint64(sum)
+}
+
+// Volume returns the volume of the set as a solid, each cuboid spanning from Min to Max
+func (s *CuboidSet) Volume() uint64 {
+	sum := int64(0)
+	for c, w := range s.weights {
+		sum += w * int64(c.Volume())
+	}
+	return uint64(sum)
+}
@@ -0,0 +1,114 @@
+package geom
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cuboidStep struct {
+	on bool
+	c  Cuboid
+}
+
+func randomCuboidSteps(r *rand.Rand, n int, size, extent int64) []cuboidStep {
+	steps := make([]cuboidStep, n)
+	for i := range steps {
+		lo := Point{X: r.Int63n(size), Y: r.Int63n(size), Z: r.Int63n(size)}
+		hi := lo.Transform(Vector{X: r.Int63n(extent), Y: r.Int63n(extent), Z: r.Int63n(extent)})
+		steps[i] = cuboidStep{on: i == 0 || r.Intn(3) > 0, c: Cuboid{Min: lo, Max: hi}}
+	}
+	return steps
+}
+
+func Test_CuboidSet(t *testing.T) {
+	// 2021 day 22's first example
+	s := NewCuboidSet(NewCuboid("10,10,10,12,12,12"), NewCuboid("11,11,11,13,13,13"))
+	assert.Equal(t, uint64(46), s.PointsCount())
+	s.Remove(NewCuboid("9,9,9,11,11,11"))
+	assert.Equal(t, uint64(38), s.PointsCount())
+	s.Add(NewCuboid("10,10,10,10,10,10"))
+	assert.Equal(t, uint64(39), s.PointsCount())
+	assert.True(t, s.Contains(NewPoint("10,10,10")))
+	assert.False(t, s.Contains(NewPoint("10,10,11")))
+
+	c := NewCuboid("-3,0,2,4,7,5")
+	s = NewCuboidSet(c)
+	assert.Equal(t, c.Volume(), s.Volume())
+	assert.Equal(t, c.PointsCount(), s.PointsCount())
+	s.Remove(c)
+	assert.Equal(t, 0, s.Len())
+
+	// cuboids apart from each other add up
+	cs := Cuboids{NewCuboid("0,0,0,2,3,4"), NewCuboid("5,0,0,6,6,6"), NewCuboid("0,8,0,1,9,1")}
+	s = NewCuboidSet(cs...)
+	assert.Equal(t, cs.Volume(), s.Volume())
+	assert.Equal(t, cs.PointsCount(), s.PointsCount())
+}
+
+func Test_CuboidSetCrossCheck(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	for i := 0; i < 20; i++ {
+		t.Run(fmt.Sprintf("Test Case %d", i+1), func(t *testing.T) {
+			steps := randomCuboidSteps(r, 6, 8, 5)
+
+			s := NewCuboidSet()
+			cs := Cuboids{}
+			for _, step := range steps {
+				if step.on {
+					s.Add(step.c)
+					cs = cs.Merge(step.c)
+				} else {
+					s.Remove(step.c)
+					cs = cs.Remove(step.c)
+				}
+			}
+			assert.Equal(t, cs.PointsCount(), s.PointsCount())
+
+			// brute force the points, and the unit cubes by their centers, which the last step covering them decides
+			points, volume := uint64(0), uint64(0)
+			for x := int64(-1); x < 14; x++ {
+				for y := int64(-1); y < 14; y++ {
+					for z := int64(-1); z < 14; z++ {
+						p := Point{X: x, Y: y, Z: z}
+						in, cube := false, false
+						for _, step := range steps {
+							if step.c.Contains(p) {
+								in = step.on
+							}
+							if step.c.Encloses(Cuboid{Min: p, Max: p.Transform(Vector{X: 1, Y: 1, Z: 1})}) {
+								cube = step.on
+							}
+						}
+						assert.Equal(t, in, s.Contains(p), "%v", p)
+						if in {
+							points++
+						}
+						if cube {
+							volume++
+						}
+					}
+				}
+			}
+			assert.Equal(t, points, s.PointsCount())
+			assert.Equal(t, volume, s.Volume())
+		})
+	}
+}
+
+func Benchmark_CuboidSet(b *testing.B) {
+	steps := randomCuboidSteps(rand.New(rand.NewSource(1)), 420, 100000, 30000)
+	for n := 0; n < b.N; n++ {
+		s := NewCuboidSet()
+		for _, step := range steps {
+			if step.on {
+				s.Add(step.c)
+			} else {
+				s.Remove(step.c)
+			}
+		}
+		_ = s.PointsCount()
+	}
+}
@@ -0,0 +1,228 @@
+package geom
+
+import (
+	"iter"
+	"sort"
+
+	"github.com/mbordner/aoc2025/common/datastructure"
+)
+
+// KDTree indexes 3D points for nearest neighbor, radius and box queries. The points are laid out as an implicit
+// balanced tree in order, the median of each range splitting it on X, Y or Z by depth. Queries return indexes into
+// the points the tree was built with.
+type KDTree[T IntNumber] struct {
+	points Positions[T]
+	order  []int
+}
+
+type neighbor[T IntNumber] struct {
+	i int
+	d T
+}
+
+// before orders neighbors by squared distance, then index, so every query has one answer even with ties
+func (n neighbor[T]) before(o neighbor[T]) bool {
+	return n.d < o.d || (n.d == o.d && n.i < o.i)
+}
+
+func cmpInt[T IntNumber](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func coord[T IntNumber](p Pos[T], axis int) T {
+	switch axis {
+	case 0:
+		return p.X
+	case 1:
+		return p.Y
+	}
+	return p.Z
+}
+
+// SquaredDistance is the squared Euclidean distance, which orders points like Distance without rounding
+func (p Pos[T]) SquaredDistance(o Pos[T]) T {
+	d := p.Subtract(o)
+	return d.X*d.X + d.Y*d.Y + d.Z*d.Z
+}
+
+func NewKDTree[T IntNumber](points Positions[T]) *KDTree[T] {
+	t := &KDTree[T]{points: points, order: make([]int, len(points))}
+	for i := range t.order {
+		t.order[i] = i
+	}
+	t.build(0, len(t.order), 0)
+	return t
+}
+
+func (t *KDTree[T]) build(lo, hi, axis int) {
+	if hi-lo < 2 {
+		return
+	}
+	o := t.order[lo:hi]
+	sort.Slice(o, func(i, j int) bool {
+		return coord(t.points[o[i]], axis) < coord(t.points[o[j]], axis)
+	})
+	mid := (lo + hi) / 2
+	t.build(lo, mid, (axis+1)%3)
+	t.build(mid+1, hi, (axis+1)%3)
+}
+
+func (t *KDTree[T]) Len() int {
+	return len(t.points)
+}
+
+func (t *KDTree[T]) Point(i int) Pos[T] {
+	return t.points[i]
+}
+
+// Nearest returns the indexes of the k points closest to q, closest first
+func (t *KDTree[T]) Nearest(q Pos[T], k int) []int {
+	return t.nearest(q, k, nil)
+}
+
+// nearest finds the k closest points to q that skip doesn't reject
+func (t *KDTree[T]) nearest(q Pos[T], k int, skip func(i int) bool) []int {
+	if k <= 0 {
+		return nil
+	}
+	// a max heap of the best so far, the worst on top
+	best := datastructure.NewAnyHeap[neighbor[T]](func(a, b neighbor[T]) int {
+		if b.before(a) {
+			return -1
+		}
+		return 1
+	})
+
+	var search func(lo, hi, axis int)
+	search = func(lo, hi, axis int) {
+		if lo >= hi {
+			return
+		}
+		mid := (lo + hi) / 2
+		i := t.order[mid]
+		p := t.points[i]
+		if skip == nil || !skip(i) {
+			n := neighbor[T]{i: i, d: p.SquaredDistance(q)}
+			if best.Len() < k {
+				best.Unshift(n)
+			} else if n.before(best.Peek()) {
+				best.Shift()
+				best.Unshift(n)
+			}
+		}
+
+		diff := coord(q, axis) - coord(p, axis)
+		near, far := [2]int{lo, mid}, [2]int{mid + 1, hi}
+		if diff >= 0 {
+			near, far = far, near
+		}
+		next := (axis + 1) % 3
+		search(near[0], near[1], next)
+		if best.Len() < k || diff*diff <= best.Peek().d {
+			search(far[0], far[1], next)
+		}
+	}
+	search(0, len(t.order), 0)
+
+	nearest := make([]int, best.Len())
+	for j := len(nearest) - 1; j >= 0; j-- {
+		nearest[j] = best.Shift().i
+	}
+	return nearest
+}
+
+// Within yields the indexes of the points no further than r from q, in no particular order
+func (t *KDTree[T]) Within(q Pos[T], r T) iter.Seq[int] {
+	bb := BoundingBox[T]{MinX: q.X - r, MaxX: q.X + r, MinY: q.Y - r, MaxY: q.Y + r, MinZ: q.Z - r, MaxZ: q.Z + r}
+	return func(yield func(int) bool) {
+		for i := range t.InBox(bb) {
+			if t.points[i].SquaredDistance(q) <= r*r && !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// InBox yields the indexes of the points inside or on bb, in no particular order
+func (t *KDTree[T]) InBox(bb BoundingBox[T]) iter.Seq[int] {
+	lower, upper := Pos[T]{X: bb.MinX, Y: bb.MinY, Z: bb.MinZ}, Pos[T]{X: bb.MaxX, Y: bb.MaxY, Z: bb.MaxZ}
+	return func(yield func(int) bool) {
+		var search func(lo, hi, axis int) bool
+		search = func(lo, hi, axis int) bool {
+			if lo >= hi {
+				return true
+			}
+			mid := (lo + hi) / 2
+			i := t.order[mid]
+			p := t.points[i]
+			if bb.Contains(p) && !yield(i) {
+				return false
+			}
+			v, next := coord(p, axis), (axis+1)%3
+			if coord(lower, axis) <= v && !search(lo, mid, next) {
+				return false
+			}
+			return coord(upper, axis) < v || search(mid+1, hi, next)
+		}
+		search(0, len(t.order), 0)
+	}
+}
+
+// Pairs yields the index pairs i < j of all the points closest first, ties in order of i then j. The pairs are found
+// lazily from a growing batch of nearest neighbors of each point, so taking the first few never builds all n² pairs.
+func (t *KDTree[T]) Pairs() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		type stream struct {
+			i         int
+			neighbors []int
+			next      int
+			n         neighbor[T]
+		}
+		streams := datastructure.NewAnyHeap[*stream](func(a, b *stream) int {
+			switch {
+			case a.n.d != b.n.d:
+				return cmpInt(a.n.d, b.n.d)
+			case a.i != b.i:
+				return cmpInt(a.i, b.i)
+			}
+			return cmpInt(a.n.i, b.n.i)
+		})
+		// advance moves s to its next neighbor, fetching twice as many when the batch runs out
+		advance := func(s *stream) bool {
+			if s.next == len(s.neighbors) {
+				if s.next == len(t.points)-1-s.i {
+					return false
+				}
+				s.neighbors = t.nearest(t.points[s.i], Max(2*len(s.neighbors), 4), func(j int) bool { return j <= s.i })
+				if s.next == len(s.neighbors) {
+					return false
+				}
+			}
+			j := s.neighbors[s.next]
+			s.n = neighbor[T]{i: j, d: t.points[s.i].SquaredDistance(t.points[j])}
+			s.next++
+			return true
+		}
+		for i := range t.points {
+			s := &stream{i: i}
+			if advance(s) {
+				streams.Unshift(s)
+			}
+		}
+		for streams.Len() > 0 {
+			s := streams.Shift()
+			if !yield(s.i, s.n.i) {
+				return
+			}
+			if advance(s) {
+				streams.Unshift(s)
+			}
+		}
+	}
+}
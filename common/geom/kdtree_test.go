@@ -0,0 +1,103 @@
+package geom
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomPositions(r *rand.Rand, n int, size int64) Positions[int64] {
+	ps := make(Positions[int64], n)
+	for i := range ps {
+		ps[i] = Pos[int64]{X: r.Int63n(size), Y: r.Int63n(size), Z: r.Int63n(size)}
+	}
+	return ps
+}
+
+func Test_KDTreeQueries(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	// a small space has lots of equal distances and repeated points
+	for _, size := range []int64{6, 1000} {
+		ps := randomPositions(r, 300, size)
+		tree := NewKDTree(ps)
+		assert.Equal(t, len(ps), tree.Len())
+
+		for q := 0; q < 50; q++ {
+			p := Pos[int64]{X: r.Int63n(size+4) - 2, Y: r.Int63n(size+4) - 2, Z: r.Int63n(size+4) - 2}
+
+			byDistance := make([]int, len(ps))
+			for i := range byDistance {
+				byDistance[i] = i
+			}
+			sort.SliceStable(byDistance, func(i, j int) bool {
+				return ps[byDistance[i]].SquaredDistance(p) < ps[byDistance[j]].SquaredDistance(p)
+			})
+			for _, k := range []int{0, 1, 5, 40, len(ps) + 1} {
+				assert.Equal(t, byDistance[:Min(k, len(ps))], append([]int{}, tree.Nearest(p, k)...), "%v %d", p, k)
+			}
+
+			radius := r.Int63n(size/2 + 1)
+			var within, inBox []int
+			bb := BoundingBox[int64]{MinX: p.X - radius, MaxX: p.X + 2*radius, MinY: p.Y, MaxY: p.Y + radius, MinZ: p.Z - radius, MaxZ: p.Z}
+			for i, o := range ps {
+				if o.SquaredDistance(p) <= radius*radius {
+					within = append(within, i)
+				}
+				if bb.Contains(o) {
+					inBox = append(inBox, i)
+				}
+			}
+			assert.ElementsMatch(t, within, slices.Collect(tree.Within(p, radius)))
+			assert.ElementsMatch(t, inBox, slices.Collect(tree.InBox(bb)))
+		}
+	}
+
+	assert.Empty(t, NewKDTree(Positions[int64]{}).Nearest(Pos[int64]{}, 3))
+}
+
+func Test_KDTreePairs(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for _, size := range []int64{5, 1000} {
+		ps := randomPositions(r, 120, size)
+		var expected [][2]int
+		for i := range ps {
+			for j := i + 1; j < len(ps); j++ {
+				expected = append(expected, [2]int{i, j})
+			}
+		}
+		sort.SliceStable(expected, func(a, b int) bool {
+			return ps[expected[a][0]].SquaredDistance(ps[expected[a][1]]) < ps[expected[b][0]].SquaredDistance(ps[expected[b][1]])
+		})
+
+		var pairs [][2]int
+		for i, j := range NewKDTree(ps).Pairs() {
+			pairs = append(pairs, [2]int{i, j})
+		}
+		assert.Equal(t, expected, pairs)
+	}
+
+	// stopping early
+	ps := randomPositions(r, 50, 100)
+	count := 0
+	for range NewKDTree(ps).Pairs() {
+		if count++; count == 10 {
+			break
+		}
+	}
+	assert.Equal(t, 10, count)
+}
+
+func Benchmark_KDTreePairs(b *testing.B) {
+	ps := randomPositions(rand.New(rand.NewSource(1)), 1000, 100000)
+	for n := 0; n < b.N; n++ {
+		count := 0
+		for range NewKDTree(ps).Pairs() {
+			if count++; count == 1000 {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package geom
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Polygon is a simple polygon through integer vertices in order, winding either way. The edge from the last vertex
+// back to the first is implied and Z is ignored. Containment includes the boundary.
+type Polygon[T IntNumber] struct {
+	vertices Positions[T]
+	// orientation is 1 if the vertices wind counterclockwise with y up, and -1 if clockwise
+	orientation T
+}
+
+func NewPolygon[T IntNumber](vertices Positions[T]) (*Polygon[T], error) {
+	vs := make(Positions[T], 0, len(vertices))
+	for _, v := range vertices {
+		vs = append(vs, Pos[T]{X: v.X, Y: v.Y})
+	}
+	if len(vs) > 1 && vs[0] == vs[len(vs)-1] {
+		vs = vs[:len(vs)-1]
+	}
+	if len(vs) < 3 {
+		return nil, errors.Errorf("a polygon needs at least 3 vertices, got %d", len(vs))
+	}
+	p := &Polygon[T]{vertices: vs, orientation: 1}
+	if p.signedDoubleArea() < 0 {
+		p.orientation = -1
+	}
+	return p, nil
+}
+
+func (p *Polygon[T]) Vertices() Positions[T] {
+	return p.vertices
+}
+
+// edge returns the edge from vertex i to the next one
+func (p *Polygon[T]) edge(i int) (a, b Pos[T]) {
+	return p.vertices[i], p.vertices[(i+1)%len(p.vertices)]
+}
+
+// IsRectilinear returns true if every edge is horizontal or vertical
+func (p *Polygon[T]) IsRectilinear() bool {
+	for i := range p.vertices {
+		a, b := p.edge(i)
+		if a.X != b.X && a.Y != b.Y {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Polygon[T]) BoundingBox() BoundingBox[T] {
+	bb := BoundingBox[T]{MinX: p.vertices[0].X, MaxX: p.vertices[0].X, MinY: p.vertices[0].Y, MaxY: p.vertices[0].Y}
+	for _, v := range p.vertices[1:] {
+		bb.Extend(v)
+	}
+	return bb
+}
+
+func cross[T IntNumber](o, a, b Pos[T]) T {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+func sign[T IntNumber](v T) T {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+func gcd[T IntNumber](a, b T) T {
+	a, b = Abs(a), Abs(b)
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func (p *Polygon[T]) signedDoubleArea() T {
+	var a T
+	for i := range p.vertices {
+		v, w := p.edge(i)
+		a += v.X*w.Y - w.X*v.Y
+	}
+	return a
+}
+
+// DoubleArea returns twice the area by the shoelace formula, which is always an integer
+func (p *Polygon[T]) DoubleArea() T {
+	return Abs(p.signedDoubleArea())
+}
+
+func (p *Polygon[T]) Area() float64 {
+	return float64(p.DoubleArea()) / 2
+}
+
+// BoundaryPoints returns how many lattice points are on the edges
+func (p *Polygon[T]) BoundaryPoints() T {
+	var b T
+	for i := range p.vertices {
+		v, w := p.edge(i)
+		b += gcd(w.X-v.X, w.Y-v.Y)
+	}
+	return b
+}
+
+// InteriorPoints returns how many lattice points are strictly inside, by Pick's theorem A = I + B/2 - 1
+func (p *Polygon[T]) InteriorPoints() T {
+	return (p.DoubleArea()-p.BoundaryPoints())/2 + 1
+}
+
+// LatticePoints returns how many lattice points are inside or on the boundary, the tiles a grid polygon covers
+func (p *Polygon[T]) LatticePoints() T {
+	return p.InteriorPoints() + p.BoundaryPoints()
+}
+
+func onSegment[T IntNumber](a, b, q Pos[T]) bool {
+	return cross(a, b, q) == 0 &&
+		q.X >= Min(a.X, b.X) && q.X <= Max(a.X, b.X) &&
+		q.Y >= Min(a.Y, b.Y) && q.Y <= Max(a.Y, b.Y)
+}
+
+// OnBoundary returns true if q is on an edge
+func (p *Polygon[T]) OnBoundary(q Pos[T]) bool {
+	for i := range p.vertices {
+		if a, b := p.edge(i); onSegment(a, b, q) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains tests q against the polygon scaled by scale, so points at half coordinates can be tested with scale 2
+func (p *Polygon[T]) contains(q Pos[T], scale T) bool {
+	inside := false
+	for i := range p.vertices {
+		a, b := p.edge(i)
+		a, b = a.Scale(scale), b.Scale(scale)
+		if onSegment(a, b, q) {
+			return true
+		}
+		// count the edges crossing the ray from q towards +x, an edge includes its lower end but not its upper
+		if (a.Y > q.Y) != (b.Y > q.Y) {
+			dy := b.Y - a.Y
+			// the crossing is right of q when ((a.X - q.X) * dy + (q.Y - a.Y) * (b.X - a.X)) / dy > 0
+			if sign((a.X-q.X)*dy+(q.Y-a.Y)*(b.X-a.X))*sign(dy) > 0 {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Contains returns true if q is inside or on the boundary
+func (p *Polygon[T]) Contains(q Pos[T]) bool {
+	return p.contains(Pos[T]{X: q.X, Y: q.Y}, 1)
+}
+
+// towardsInside returns true if the polygon includes the points just past q, which is on the boundary, in direction d
+func (p *Polygon[T]) towardsInside(q, d Pos[T]) bool {
+	n := len(p.vertices)
+	for i, v := range p.vertices {
+		if v != q {
+			continue
+		}
+		// the interior lies counterclockwise from the outgoing edge to the incoming one, turning through the inside
+		out, in := p.vertices[(i+1)%n].Subtract(v), p.vertices[(i+n-1)%n].Subtract(v)
+		if p.orientation < 0 {
+			out, in = in, out
+		}
+		var o Pos[T]
+		if cross(o, out, in) >= 0 {
+			return cross(o, out, d) >= 0 && cross(o, d, in) >= 0
+		}
+		return !(cross(o, in, d) > 0 && cross(o, d, out) > 0)
+	}
+	for i := range p.vertices {
+		if a, b := p.edge(i); onSegment(a, b, q) {
+			var o Pos[T]
+			return cross(o, b.Subtract(a), d)*p.orientation >= 0
+		}
+	}
+	return p.Contains(q)
+}
+
+// ContainsRect returns true if the axis aligned rectangle with opposite corners c0 and c1 is entirely inside or on the
+// boundary. The rectangle can be flat, a segment or a single point.
+func (p *Polygon[T]) ContainsRect(c0, c1 Pos[T]) bool {
+	x0, x1 := Min(c0.X, c1.X), Max(c0.X, c1.X)
+	y0, y1 := Min(c0.Y, c1.Y), Max(c0.Y, c1.Y)
+	if x0 == x1 || y0 == y1 {
+		return p.containsSegment(Pos[T]{X: x0, Y: y0}, Pos[T]{X: x1, Y: y1})
+	}
+
+	// no edge can enter the open rectangle, then it is all inside or all outside, which its center tells
+	corners := Positions[T]{{X: x0, Y: y0}, {X: x1, Y: y0}, {X: x1, Y: y1}, {X: x0, Y: y1}}
+	for i := range p.vertices {
+		a, b := p.edge(i)
+		if Min(a.X, b.X) >= x1 || Max(a.X, b.X) <= x0 || Min(a.Y, b.Y) >= y1 || Max(a.Y, b.Y) <= y0 {
+			continue
+		}
+		left, right := false, false
+		for _, c := range corners {
+			switch s := cross(a, b, c); {
+			case s > 0:
+				left = true
+			case s < 0:
+				right = true
+			}
+		}
+		if left && right {
+			return false
+		}
+	}
+	return p.contains(Pos[T]{X: x0 + x1, Y: y0 + y1}, 2)
+}
+
+// containsSegment tests the horizontal or vertical segment from s0 to s1, which are ordered
+func (p *Polygon[T]) containsSegment(s0, s1 Pos[T]) bool {
+	if s0 == s1 {
+		return p.Contains(s0)
+	}
+	d := s1.Subtract(s0).Normalize()
+	back := d.Scale(-1)
+
+	for _, end := range []struct{ q, d Pos[T] }{{s0, d}, {s1, back}} {
+		if p.OnBoundary(end.q) {
+			if !p.towardsInside(end.q, end.d) {
+				return false
+			}
+		} else if !p.Contains(end.q) {
+			return false
+		}
+	}
+
+	within := func(q Pos[T]) bool {
+		return q != s0 && q != s1 && onSegment(s0, s1, q)
+	}
+	for i, v := range p.vertices {
+		// the segment passes through a vertex, it has to stay inside on both sides
+		if within(v) && (!p.towardsInside(v, d) || !p.towardsInside(v, back)) {
+			return false
+		}
+		// an edge crossing the segment away from its vertices leaves the polygon on one side
+		a, b := p.edge(i)
+		if sa, sb := sign(cross(s0, s1, a)), sign(cross(s0, s1, b)); sa*sb < 0 {
+			if sign(cross(a, b, s0))*sign(cross(a, b, s1)) < 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package geom
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the red tiles of 2025 day 9's example
+var theater = Positions[int]{{X: 7, Y: 1}, {X: 11, Y: 1}, {X: 11, Y: 7}, {X: 9, Y: 7}, {X: 9, Y: 5}, {X: 2, Y: 5}, {X: 2, Y: 3}, {X: 7, Y: 3}}
+
+func Test_PolygonLatticePoints(t *testing.T) {
+	cases := []struct {
+		vertices    Positions[int]
+		doubleArea  int
+		boundary    int
+		rectilinear bool
+	}{
+		{vertices: theater, doubleArea: 2 * 30, boundary: 30, rectilinear: true},
+		{vertices: Positions[int]{{X: 0, Y: 0}, {X: 6, Y: 0}, {X: 0, Y: 4}}, doubleArea: 24, boundary: 12},
+		{vertices: Positions[int]{{X: 0, Y: 0}, {X: 8, Y: 2}, {X: 3, Y: 3}, {X: 2, Y: 8}}, doubleArea: 36, boundary: 6},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("Test Case %d", i+1), func(t *testing.T) {
+			p, err := NewPolygon(tc.vertices)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.rectilinear, p.IsRectilinear())
+			assert.Equal(t, tc.doubleArea, p.DoubleArea())
+			assert.Equal(t, tc.boundary, p.BoundaryPoints())
+
+			// the other winding gives the same answers
+			reversed := make(Positions[int], 0, len(tc.vertices))
+			for j := len(tc.vertices) - 1; j >= 0; j-- {
+				reversed = append(reversed, tc.vertices[j])
+			}
+			r, _ := NewPolygon(reversed)
+			assert.Equal(t, tc.doubleArea, r.DoubleArea())
+
+			interior, boundary := 0, 0
+			bb := p.BoundingBox()
+			for y := bb.MinY - 1; y <= bb.MaxY+1; y++ {
+				for x := bb.MinX - 1; x <= bb.MaxX+1; x++ {
+					q := Pos[int]{X: x, Y: y}
+					assert.Equal(t, p.Contains(q), r.Contains(q))
+					switch {
+					case p.OnBoundary(q):
+						boundary++
+					case p.Contains(q):
+						interior++
+					}
+				}
+			}
+			assert.Equal(t, boundary, p.BoundaryPoints())
+			assert.Equal(t, interior, p.InteriorPoints())
+			assert.Equal(t, interior+boundary, p.LatticePoints())
+		})
+	}
+
+	_, err := NewPolygon(Positions[int]{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 0}})
+	assert.Error(t, err)
+}
+
+func Test_PolygonContainsRect(t *testing.T) {
+	// a U whose arms are one apart at the top, the gap between them is outside
+	u := Positions[int]{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 5, Y: 6}, {X: 3, Y: 6}, {X: 3, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 6}, {X: 0, Y: 6}}
+
+	for _, vertices := range []Positions[int]{theater, u} {
+		p, _ := NewPolygon(vertices)
+		bb := p.BoundingBox()
+		for x0 := bb.MinX - 1; x0 <= bb.MaxX+1; x0++ {
+			for y0 := bb.MinY - 1; y0 <= bb.MaxY+1; y0++ {
+				for x1 := x0; x1 <= bb.MaxX+1; x1++ {
+					for y1 := y0; y1 <= bb.MaxY+1; y1++ {
+						// the boundary of a rectilinear polygon is on whole coordinates, so sampling every half
+						// coordinate of the rectangle decides it exactly
+						expected := true
+						for x := 2 * x0; x <= 2*x1 && expected; x++ {
+							for y := 2 * y0; y <= 2*y1 && expected; y++ {
+								expected = p.contains(Pos[int]{X: x, Y: y}, 2)
+							}
+						}
+						c0, c1 := Pos[int]{X: x0, Y: y1}, Pos[int]{X: x1, Y: y0}
+						assert.Equal(t, expected, p.ContainsRect(c0, c1), "%v %v", c0, c1)
+					}
+				}
+			}
+		}
+	}
+
+	triangle, _ := NewPolygon(Positions[int]{{X: 0, Y: 0}, {X: 6, Y: 0}, {X: 0, Y: 6}})
+	assert.True(t, triangle.ContainsRect(Pos[int]{X: 0, Y: 0}, Pos[int]{X: 3, Y: 3}))
+	assert.False(t, triangle.ContainsRect(Pos[int]{X: 0, Y: 0}, Pos[int]{X: 3, Y: 4}))
+	assert.True(t, triangle.ContainsRect(Pos[int]{X: 1, Y: 5}, Pos[int]{X: 0, Y: 5}))
+	assert.False(t, triangle.ContainsRect(Pos[int]{X: 2, Y: 5}, Pos[int]{X: 0, Y: 5}))
+
+	// a segment along the top of the U crossing the gap
+	p, _ := NewPolygon(u)
+	assert.False(t, p.ContainsRect(Pos[int]{X: 0, Y: 6}, Pos[int]{X: 5, Y: 6}))
+	assert.True(t, p.ContainsRect(Pos[int]{X: 0, Y: 2}, Pos[int]{X: 5, Y: 2}))
+}
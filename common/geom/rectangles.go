@@ -0,0 +1,125 @@
+package geom
+
+import (
+	"github.com/pkg/errors"
+)
+
+// RectangleIndex answers whether axis aligned rectangles with their corners on a rectilinear polygon's vertex
+// coordinates are inside the polygon in O(1). The x and y values of the vertices are compressed to indexes, with odd
+// indexes for the gaps between them, so every cell, line and point of the compressed grid is either all inside or all
+// outside. A rectangle is inside if none of its grid elements are outside, which a 2D prefix sum counts.
+type RectangleIndex[T IntNumber] struct {
+	polygon *Polygon[T]
+	xs, ys  *Compression[T]
+	// outside[i][j] counts the outside elements with doubled indexes below i and j
+	outside [][]int32
+}
+
+// NewRectangleIndex builds the index in O(n²) for a polygon with n vertices
+func NewRectangleIndex[T IntNumber](p *Polygon[T]) (*RectangleIndex[T], error) {
+	if !p.IsRectilinear() {
+		return nil, errors.New("rectangle index needs a rectilinear polygon")
+	}
+	ri := &RectangleIndex[T]{polygon: p}
+	xs := make([]T, len(p.vertices))
+	ys := make([]T, len(p.vertices))
+	for i, v := range p.vertices {
+		xs[i], ys[i] = v.X, v.Y
+	}
+	ri.xs, ri.ys = NewCompression(xs...), NewCompression(ys...)
+
+	// a cell is inside if an odd number of vertical edges are left of it
+	nx, ny := len(ri.xs.Breakpoints()), len(ri.ys.Breakpoints())
+	inside := make([][]bool, nx+1)
+	for i := range inside {
+		inside[i] = make([]bool, ny+1)
+	}
+	for i := range p.vertices {
+		a, b := p.edge(i)
+		if a.X != b.X {
+			continue
+		}
+		x, _ := ri.xs.Breakpoint(a.X)
+		y0, _ := ri.ys.Breakpoint(Min(a.Y, b.Y))
+		y1, _ := ri.ys.Breakpoint(Max(a.Y, b.Y))
+		for y := y0; y < y1; y++ {
+			inside[x][y] = !inside[x][y]
+		}
+	}
+	for x := 1; x < nx; x++ {
+		for y := 0; y < ny; y++ {
+			inside[x][y] = inside[x][y] != inside[x-1][y]
+		}
+	}
+	// inside[x][y] is now the cell right of xs[x] and above ys[y], the polygon is closed so a line or point is inside
+	// if any cell around it is
+	cell := func(x, y int) bool {
+		return x >= 0 && y >= 0 && inside[x][y]
+	}
+
+	w, h := 2*nx-1, 2*ny-1
+	ri.outside = make([][]int32, w+1)
+	for i := range ri.outside {
+		ri.outside[i] = make([]int32, h+1)
+	}
+	for i := 0; i < w; i++ {
+		for j := 0; j < h; j++ {
+			in := false
+			for _, x := range []int{(i - 1) / 2, i / 2} {
+				for _, y := range []int{(j - 1) / 2, j / 2} {
+					if (i%2 == 0 || x == i/2) && (j%2 == 0 || y == j/2) && cell(x, y) {
+						in = true
+					}
+				}
+			}
+			ri.outside[i+1][j+1] = ri.outside[i][j+1] + ri.outside[i+1][j] - ri.outside[i][j]
+			if !in {
+				ri.outside[i+1][j+1]++
+			}
+		}
+	}
+	return ri, nil
+}
+
+// Contains returns true if the rectangle with opposite corners c0 and c1 is inside the polygon, the corners have to be
+// on vertex x and y values
+func (ri *RectangleIndex[T]) Contains(c0, c1 Pos[T]) (bool, error) {
+	x0, ok0 := ri.xs.Breakpoint(Min(c0.X, c1.X))
+	x1, ok1 := ri.xs.Breakpoint(Max(c0.X, c1.X))
+	y0, ok2 := ri.ys.Breakpoint(Min(c0.Y, c1.Y))
+	y1, ok3 := ri.ys.Breakpoint(Max(c0.Y, c1.Y))
+	if !ok0 || !ok1 || !ok2 || !ok3 {
+		return false, errors.Errorf("rectangle %v %v isn't on the polygon's vertex coordinates", c0, c1)
+	}
+	x0, x1, y0, y1 = 2*x0, 2*x1+1, 2*y0, 2*y1+1
+	return ri.outside[x1][y1]-ri.outside[x0][y1]-ri.outside[x1][y0]+ri.outside[x0][y0] == 0, nil
+}
+
+// RectangleArea is the area of the rectangle with opposite corners c0 and c1
+func RectangleArea[T IntNumber](c0, c1 Pos[T]) T {
+	return Abs(c1.X-c0.X) * Abs(c1.Y-c0.Y)
+}
+
+// RectangleTiles is how many lattice points the rectangle with opposite corners c0 and c1 covers
+func RectangleTiles[T IntNumber](c0, c1 Pos[T]) T {
+	return (Abs(c1.X-c0.X) + 1) * (Abs(c1.Y-c0.Y) + 1)
+}
+
+// Largest returns the opposite corners and area of the largest rectangle inside the polygon with two vertices as
+// opposite corners, measuring them with area such as RectangleArea or RectangleTiles
+func (ri *RectangleIndex[T]) Largest(area func(c0, c1 Pos[T]) T) (c0, c1 Pos[T], best T) {
+	vs := ri.polygon.vertices
+	found := false
+	for i := 0; i < len(vs)-1; i++ {
+		for j := i + 1; j < len(vs); j++ {
+			a := area(vs[i], vs[j])
+			if found && a <= best {
+				continue
+			}
+			if inside, _ := ri.Contains(vs[i], vs[j]); inside {
+				c0, c1, best, found = vs[i], vs[j], a, true
+			}
+		}
+	}
+	return c0, c1, best
+}
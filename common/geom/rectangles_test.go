@@ -0,0 +1,45 @@
+package geom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RectangleIndex(t *testing.T) {
+	u := Positions[int]{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 5, Y: 6}, {X: 3, Y: 6}, {X: 3, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 6}, {X: 0, Y: 6}}
+	stairs := Positions[int]{{X: 0, Y: 0}, {X: 40, Y: 0}, {X: 40, Y: 10}, {X: 30, Y: 10}, {X: 30, Y: 25}, {X: 10, Y: 25}, {X: 10, Y: 30}, {X: 0, Y: 30}}
+
+	for _, vertices := range []Positions[int]{theater, u, stairs} {
+		p, _ := NewPolygon(vertices)
+		ri, err := NewRectangleIndex(p)
+		assert.NoError(t, err)
+		for _, x0 := range ri.xs.Breakpoints() {
+			for _, y0 := range ri.ys.Breakpoints() {
+				for _, x1 := range ri.xs.Breakpoints() {
+					for _, y1 := range ri.ys.Breakpoints() {
+						c0, c1 := Pos[int]{X: x0, Y: y0}, Pos[int]{X: x1, Y: y1}
+						inside, err := ri.Contains(c0, c1)
+						assert.NoError(t, err)
+						assert.Equal(t, p.ContainsRect(c0, c1), inside, "%v %v", c0, c1)
+					}
+				}
+			}
+		}
+	}
+
+	p, _ := NewPolygon(theater)
+	ri, _ := NewRectangleIndex(p)
+	c0, c1, tiles := ri.Largest(RectangleTiles[int])
+	assert.Equal(t, 24, tiles)
+	assert.Equal(t, 24, RectangleTiles(c0, c1))
+	_, _, area := ri.Largest(RectangleArea[int])
+	assert.Equal(t, 14, area)
+
+	_, err := ri.Contains(Pos[int]{X: 7, Y: 1}, Pos[int]{X: 8, Y: 3})
+	assert.Error(t, err)
+
+	triangle, _ := NewPolygon(Positions[int]{{X: 0, Y: 0}, {X: 6, Y: 0}, {X: 0, Y: 6}})
+	_, err = NewRectangleIndex(triangle)
+	assert.Error(t, err)
+}
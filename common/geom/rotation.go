@@ -0,0 +1,129 @@
+package geom
+
+// Rotation is a proper rotation of 3D space mapping axes onto axes, as an integer matrix applied to column vectors
+type Rotation [3][3]int64
+
+var rotations = func() []Rotation {
+	// the signed permutation matrices with determinant 1, the identity first
+	var rs []Rotation
+	perms := [][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	for _, perm := range perms {
+		for signs := 0; signs < 8; signs++ {
+			var r Rotation
+			for row, col := range perm {
+				r[row][col] = 1
+				if signs&(1<<row) != 0 {
+					r[row][col] = -1
+				}
+			}
+			if r.determinant() == 1 {
+				rs = append(rs, r)
+			}
+		}
+	}
+	return rs
+}()
+
+// Rotations returns a copy of the 24 rotations, starting with the identity
+func Rotations() []Rotation {
+	return append([]Rotation(nil), rotations...)
+}
+
+func (r Rotation) determinant() int64 {
+	return r[0][0]*(r[1][1]*r[2][2]-r[1][2]*r[2][1]) -
+		r[0][1]*(r[1][0]*r[2][2]-r[1][2]*r[2][0]) +
+		r[0][2]*(r[1][0]*r[2][1]-r[1][1]*r[2][0])
+}
+
+// Then returns the rotation doing r and then o
+func (r Rotation) Then(o Rotation) Rotation {
+	var n Rotation
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				n[i][j] += o[i][k] * r[k][j]
+			}
+		}
+	}
+	return n
+}
+
+// Inverse is the transpose, as rotation matrices are orthogonal
+func (r Rotation) Inverse() Rotation {
+	var n Rotation
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			n[i][j] = r[j][i]
+		}
+	}
+	return n
+}
+
+func (r Rotation) apply(x, y, z int64) (int64, int64, int64) {
+	return r[0][0]*x + r[0][1]*y + r[0][2]*z,
+		r[1][0]*x + r[1][1]*y + r[1][2]*z,
+		r[2][0]*x + r[2][1]*y + r[2][2]*z
+}
+
+// Rotate turns p about the origin
+func (p Point) Rotate(r Rotation) Point {
+	x, y, z := r.apply(p.X, p.Y, p.Z)
+	return Point{X: x, Y: y, Z: z}
+}
+
+func (ps Points) Rotate(r Rotation) Points {
+	ns := make(Points, len(ps))
+	for i := range ps {
+		ns[i] = ps[i].Rotate(r)
+	}
+	return ns
+}
+
+// Rotate turns p about the origin. It only permutes and negates coordinates, so it relies on T being signed, which
+// IntNumber guarantees; as with plain negation the minimum value of T wraps to itself.
+func (p Pos[T]) Rotate(r Rotation) Pos[T] {
+	x, y, z := r.apply(int64(p.X), int64(p.Y), int64(p.Z))
+	return Pos[T]{X: T(x), Y: T(y), Z: T(z)}
+}
+
+func (ps Positions[T]) Rotate(r Rotation) Positions[T] {
+	ns := make(Positions[T], len(ps))
+	for i := range ps {
+		ns[i] = ps[i].Rotate(r)
+	}
+	return ns
+}
+
+// Rotate turns c about the origin, the corners swapped around so Min stays the minimum
+func (c Cuboid) Rotate(r Rotation) Cuboid {
+	a, b := c.Min.Rotate(r), c.Max.Rotate(r)
+	return Cuboid{Min: a.Min(b), Max: a.Max(b)}
+}
+
+// Alignment places a point cloud in the frame of a reference cloud, other.Rotate(Rotation).Transform(Offset) matching
+// Matched of the reference points
+type Alignment struct {
+	Rotation Rotation
+	Offset   Vector
+	Matched  int
+}
+
+// Align tries every rotation of other and every offset putting one of its points on one of the reference's, and
+// returns the alignment matching the most points. It is false if the best matches fewer than minMatched points.
+func Align(reference, other Points, minMatched int) (Alignment, bool) {
+	var best Alignment
+	for _, r := range rotations {
+		rotated := other.Rotate(r)
+		counts := make(map[Vector]int)
+		for _, p := range reference {
+			for _, q := range rotated {
+				offset := Vector{X: p.X - q.X, Y: p.Y - q.Y, Z: p.Z - q.Z}
+				counts[offset]++
+				if counts[offset] > best.Matched {
+					best = Alignment{Rotation: r, Offset: offset, Matched: counts[offset]}
+				}
+			}
+		}
+	}
+	return best, best.Matched > 0 && best.Matched >= minMatched
+}
@@ -0,0 +1,84 @@
+package geom
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Rotations(t *testing.T) {
+	rs := Rotations()
+	assert.Len(t, rs, 24)
+	assert.Equal(t, Rotation{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}, rs[0])
+
+	// callers get their own copy
+	rs[0] = Rotation{}
+	assert.Equal(t, Rotation{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}, Rotations()[0])
+	rs = Rotations()
+
+	p := Point{X: 1, Y: 2, Z: 3}
+	images := make(map[Point]bool)
+	group := make(map[Rotation]bool)
+	for _, r := range rs {
+		group[r] = true
+		images[p.Rotate(r)] = true
+		assert.Equal(t, int64(1), r.determinant())
+		assert.Equal(t, rs[0], r.Then(r.Inverse()))
+		assert.Equal(t, p, p.Rotate(r).Rotate(r.Inverse()))
+	}
+	assert.Len(t, images, 24)
+	for _, r := range rs {
+		for _, o := range rs {
+			assert.True(t, group[r.Then(o)])
+			assert.Equal(t, p.Rotate(r).Rotate(o), p.Rotate(r.Then(o)))
+		}
+	}
+
+	// a quarter turn about z takes x to y
+	quarter := Rotation{{0, -1, 0}, {1, 0, 0}, {0, 0, 1}}
+	assert.Equal(t, Point{X: -2, Y: 1, Z: 3}, p.Rotate(quarter))
+	assert.Equal(t, Pos[int]{X: -2, Y: 1, Z: 3}, Pos[int]{X: 1, Y: 2, Z: 3}.Rotate(quarter))
+	assert.Equal(t, Positions[int]{{X: 0, Y: 1}, {X: -1, Y: 0}}, Positions[int]{{X: 1}, {Y: 1}}.Rotate(quarter))
+
+	c := NewCuboid("1,2,3,4,8,5")
+	for _, r := range rs {
+		rc := c.Rotate(r)
+		assert.Equal(t, c.PointsCount(), rc.PointsCount())
+		for _, q := range c.Corners() {
+			assert.True(t, rc.Contains(q.Rotate(r)))
+		}
+	}
+}
+
+func Test_Align(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	reference := make(Points, 0, 25)
+	for len(reference) < 25 {
+		p := Point{X: r.Int63n(2000) - 1000, Y: r.Int63n(2000) - 1000, Z: r.Int63n(2000) - 1000}
+		if !reference.Contains(p) {
+			reference = append(reference, p)
+		}
+	}
+
+	rotation := Rotations()[17]
+	offset := Vector{X: 68, Y: -1246, Z: -43}
+	// other sees 12 of the reference points from its own position and orientation, and some of its own
+	var other Points
+	for _, p := range reference[:12] {
+		other = append(other, p.Transform(Vector{X: -offset.X, Y: -offset.Y, Z: -offset.Z}).Rotate(rotation.Inverse()))
+	}
+	for i := 0; i < 10; i++ {
+		other = append(other, Point{X: r.Int63n(2000) + 5000, Y: r.Int63n(2000), Z: r.Int63n(2000)})
+	}
+
+	a, ok := Align(reference, other, 12)
+	assert.True(t, ok)
+	assert.Equal(t, Alignment{Rotation: rotation, Offset: offset, Matched: 12}, a)
+	for _, p := range other[:12] {
+		assert.True(t, reference.Contains(p.Rotate(a.Rotation).Transform(a.Offset)))
+	}
+
+	_, ok = Align(reference, other[12:], 3)
+	assert.False(t, ok)
+}
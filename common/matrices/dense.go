@@ -0,0 +1,305 @@
+package matrices
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+var ErrSingular = errors.New("matrix is singular")
+
+type Number interface {
+	int | int32 | int64 | float32 | float64
+}
+
+type Integer interface {
+	int | int32 | int64
+}
+
+// Matrix is a dense rows x cols matrix stored in row major order
+type Matrix[T Number] struct {
+	rows int
+	cols int
+	data []T
+}
+
+func NewMatrix[T Number](rows, cols int) *Matrix[T] {
+	return &Matrix[T]{rows: rows, cols: cols, data: make([]T, rows*cols)}
+}
+
+// NewMatrixFromRows copies rows into a Matrix, they must all have the same length
+func NewMatrixFromRows[T Number](rows [][]T) (*Matrix[T], error) {
+	cols := 0
+	if len(rows) > 0 {
+		cols = len(rows[0])
+	}
+	m := NewMatrix[T](len(rows), cols)
+	for i, row := range rows {
+		if len(row) != cols {
+			return nil, errors.Errorf("row %d has %d columns, expected %d", i, len(row), cols)
+		}
+		copy(m.data[i*cols:], row)
+	}
+	return m, nil
+}
+
+func Identity[T Number](n int) *Matrix[T] {
+	m := NewMatrix[T](n, n)
+	for i := 0; i < n; i++ {
+		m.data[i*n+i] = 1
+	}
+	return m
+}
+
+func (m *Matrix[T]) Rows() int {
+	return m.rows
+}
+
+func (m *Matrix[T]) Cols() int {
+	return m.cols
+}
+
+func (m *Matrix[T]) At(i, j int) T {
+	return m.data[i*m.cols+j]
+}
+
+func (m *Matrix[T]) Set(i, j int, v T) {
+	m.data[i*m.cols+j] = v
+}
+
+// ToRows copies the matrix out as a slice of rows
+func (m *Matrix[T]) ToRows() [][]T {
+	rows := make([][]T, m.rows)
+	for i := range rows {
+		rows[i] = append([]T{}, m.data[i*m.cols:(i+1)*m.cols]...)
+	}
+	return rows
+}
+
+func (m *Matrix[T]) String() string {
+	return fmt.Sprint(m.ToRows())
+}
+
+func (m *Matrix[T]) Clone() *Matrix[T] {
+	return &Matrix[T]{rows: m.rows, cols: m.cols, data: append([]T{}, m.data...)}
+}
+
+func (m *Matrix[T]) Transpose() *Matrix[T] {
+	t := NewMatrix[T](m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			t.data[j*m.rows+i] = m.data[i*m.cols+j]
+		}
+	}
+	return t
+}
+
+// Mul returns the product m * o
+func (m *Matrix[T]) Mul(o *Matrix[T]) (*Matrix[T], error) {
+	if m.cols != o.rows {
+		return nil, errors.Errorf("can't multiply %dx%d by %dx%d", m.rows, m.cols, o.rows, o.cols)
+	}
+	p := NewMatrix[T](m.rows, o.cols)
+	for i := 0; i < m.rows; i++ {
+		for k := 0; k < m.cols; k++ {
+			a := m.data[i*m.cols+k]
+			if a == 0 {
+				continue
+			}
+			for j := 0; j < o.cols; j++ {
+				p.data[i*o.cols+j] += a * o.data[k*o.cols+j]
+			}
+		}
+	}
+	return p, nil
+}
+
+// Pow returns m^n by repeated squaring, m^0 is the identity
+func (m *Matrix[T]) Pow(n uint64) (*Matrix[T], error) {
+	return pow(m, n, (*Matrix[T]).Mul)
+}
+
+func pow[T Number](m *Matrix[T], n uint64, mul func(a, b *Matrix[T]) (*Matrix[T], error)) (*Matrix[T], error) {
+	if m.rows != m.cols {
+		return nil, errors.Errorf("can't raise %dx%d matrix to a power", m.rows, m.cols)
+	}
+	result := Identity[T](m.rows)
+	square := m
+	var err error
+	for n > 0 {
+		if n&1 == 1 {
+			if result, err = mul(result, square); err != nil {
+				return nil, err
+			}
+		}
+		n >>= 1
+		if n > 0 {
+			if square, err = mul(square, square); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// mulMod returns a*b mod m for a, b in [0, m) without overflowing
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m)
+}
+
+// reduce returns v mod m in [0, m)
+func reduce[T Integer](v, m T) uint64 {
+	r := v % m
+	if r < 0 {
+		r += m
+	}
+	return uint64(r)
+}
+
+// MulMod returns the product a * b with every entry reduced into [0, mod)
+func MulMod[T Integer](a, b *Matrix[T], mod T) (*Matrix[T], error) {
+	if a.cols != b.rows {
+		return nil, errors.Errorf("can't multiply %dx%d by %dx%d", a.rows, a.cols, b.rows, b.cols)
+	}
+	if mod <= 0 {
+		return nil, errors.Errorf("invalid modulus %d", mod)
+	}
+	m := uint64(mod)
+	p := NewMatrix[T](a.rows, b.cols)
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < b.cols; j++ {
+			sum := uint64(0)
+			for k := 0; k < a.cols; k++ {
+				sum = (sum + mulMod(reduce(a.data[i*a.cols+k], mod), reduce(b.data[k*b.cols+j], mod), m)) % m
+			}
+			p.data[i*b.cols+j] = T(sum)
+		}
+	}
+	return p, nil
+}
+
+// PowMod returns m^n with every entry reduced into [0, mod), which keeps linear recurrences exact however far they're
+// advanced
+func PowMod[T Integer](m *Matrix[T], n uint64, mod T) (*Matrix[T], error) {
+	if mod <= 0 {
+		return nil, errors.Errorf("invalid modulus %d", mod)
+	}
+	p, err := pow(m, n, func(a, b *Matrix[T]) (*Matrix[T], error) {
+		return MulMod(a, b, mod)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// m^0 is the identity, which no multiplication has reduced
+	for i, v := range p.data {
+		p.data[i] = T(reduce(v, mod))
+	}
+	return p, nil
+}
+
+// toRat returns the entries as exact rationals, or an error for NaN and infinite entries, which have none
+func (m *Matrix[T]) toRat() ([][]*big.Rat, error) {
+	R := make([][]*big.Rat, m.rows)
+	for i := range R {
+		R[i] = make([]*big.Rat, m.cols)
+		for j := range R[i] {
+			var f float64
+			switch v := any(m.data[i*m.cols+j]).(type) {
+			case float32:
+				f = float64(v)
+			case float64:
+				f = v
+			default:
+				R[i][j] = new(big.Rat).SetInt64(int64(m.data[i*m.cols+j]))
+				continue
+			}
+			if R[i][j] = new(big.Rat).SetFloat64(f); R[i][j] == nil {
+				return nil, errors.Errorf("entry %d,%d is %v, which isn't a rational", i, j, f)
+			}
+		}
+	}
+	return R, nil
+}
+
+// Determinant computes the exact determinant with Bareiss' fraction free elimination, every division it does is
+// exact so integer matrices never leave the integers. Float entries are taken at their exact binary value.
+func (m *Matrix[T]) Determinant() (*big.Rat, error) {
+	if m.rows != m.cols {
+		return nil, errors.Errorf("%dx%d matrix has no determinant", m.rows, m.cols)
+	}
+	n := m.rows
+	if n == 0 {
+		return big.NewRat(1, 1), nil
+	}
+	A, err := m.toRat()
+	if err != nil {
+		return nil, err
+	}
+	sign := 1
+	prev := big.NewRat(1, 1)
+	for k := 0; k < n; k++ {
+		if A[k][k].Sign() == 0 {
+			swap := -1
+			for i := k + 1; i < n; i++ {
+				if A[i][k].Sign() != 0 {
+					swap = i
+					break
+				}
+			}
+			if swap == -1 {
+				return new(big.Rat), nil
+			}
+			A[k], A[swap] = A[swap], A[k]
+			sign = -sign
+		}
+		for i := k + 1; i < n; i++ {
+			for j := k + 1; j < n; j++ {
+				// A[i][j] = (A[i][j]*A[k][k] - A[i][k]*A[k][j]) / prev
+				v := new(big.Rat).Mul(A[i][j], A[k][k])
+				v.Sub(v, new(big.Rat).Mul(A[i][k], A[k][j]))
+				A[i][j] = v.Quo(v, prev)
+			}
+		}
+		prev = A[k][k]
+	}
+	det := new(big.Rat).Set(A[n-1][n-1])
+	if sign < 0 {
+		det.Neg(det)
+	}
+	return det, nil
+}
+
+// Inverse returns the exact inverse over the rationals by reducing [m | I], or ErrSingular
+func (m *Matrix[T]) Inverse() ([][]*big.Rat, error) {
+	if m.rows != m.cols {
+		return nil, errors.Errorf("%dx%d matrix has no inverse", m.rows, m.cols)
+	}
+	n := m.rows
+	A, err := m.toRat()
+	if err != nil {
+		return nil, err
+	}
+	for i := range A {
+		for j := 0; j < n; j++ {
+			v := new(big.Rat)
+			if i == j {
+				v.SetInt64(1)
+			}
+			A[i] = append(A[i], v)
+		}
+	}
+	ToRationalReducedEchelonForm(A)
+	for i := 0; i < n; i++ {
+		if A[i][i].Cmp(big.NewRat(1, 1)) != 0 {
+			return nil, ErrSingular
+		}
+	}
+	inverse := make([][]*big.Rat, n)
+	for i := range inverse {
+		inverse[i] = A[i][n:]
+	}
+	return inverse, nil
+}
@@ -0,0 +1,149 @@
+package matrices
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MatrixMulTranspose(t *testing.T) {
+	a, err := NewMatrixFromRows([][]int64{{1, 2, 3}, {4, 5, 6}})
+	assert.NoError(t, err)
+	b, err := NewMatrixFromRows([][]int64{{7, 8}, {9, 10}, {11, 12}})
+	assert.NoError(t, err)
+
+	p, err := a.Mul(b)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int64{{58, 64}, {139, 154}}, p.ToRows())
+
+	assert.Equal(t, [][]int64{{1, 4}, {2, 5}, {3, 6}}, a.Transpose().ToRows())
+	assert.Equal(t, "[[1 2 3] [4 5 6]]", a.String())
+
+	_, err = a.Mul(a)
+	assert.Error(t, err)
+
+	_, err = NewMatrixFromRows([][]int64{{1, 2}, {3}})
+	assert.Error(t, err)
+
+	i := Identity[float64](2)
+	f, _ := NewMatrixFromRows([][]float64{{0.5, 1}, {2, 4}})
+	p2, err := f.Mul(i)
+	assert.NoError(t, err)
+	assert.Equal(t, f.ToRows(), p2.ToRows())
+}
+
+func fibonacci() *Matrix[int64] {
+	m, _ := NewMatrixFromRows([][]int64{{1, 1}, {1, 0}})
+	return m
+}
+
+func Test_MatrixPow(t *testing.T) {
+	fib := []int64{0, 1}
+	for len(fib) <= 90 {
+		fib = append(fib, fib[len(fib)-1]+fib[len(fib)-2])
+	}
+
+	p, err := fibonacci().Pow(90)
+	assert.NoError(t, err)
+	assert.Equal(t, fib[90], p.At(0, 1))
+
+	p, err = fibonacci().Pow(0)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int64{{1, 0}, {0, 1}}, p.ToRows())
+
+	_, err = NewMatrix[int64](2, 3).Pow(2)
+	assert.Error(t, err)
+}
+
+func Test_PowMod(t *testing.T) {
+	// fibonacci numbers mod 1000 repeat with the pisano period 1500
+	const n = 1_000_000_000_000
+	a, b := int64(0), int64(1)
+	for i := 0; i < n%1500; i++ {
+		a, b = b, (a+b)%1000
+	}
+	p, err := PowMod(fibonacci(), n, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, a, p.At(0, 1))
+
+	// a modulus near 2^62 overflows a plain int64 product
+	const mod = int64(1) << 62
+	p, err = PowMod(fibonacci(), 90, mod)
+	assert.NoError(t, err)
+	q, _ := fibonacci().Pow(90)
+	assert.Equal(t, q.At(0, 0)%mod, p.At(0, 0))
+
+	// negative entries reduce into [0, mod)
+	neg, _ := NewMatrixFromRows([][]int64{{-1}})
+	p, err = PowMod(neg, 3, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), p.At(0, 0))
+
+	_, err = PowMod(neg, 3, 0)
+	assert.Error(t, err)
+
+	// m^0 is the identity reduced too, all zeros mod 1
+	p, err = PowMod(fibonacci(), 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int64{{0, 0}, {0, 0}}, p.ToRows())
+	p, err = PowMod(fibonacci(), 0, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int64{{1, 0}, {0, 1}}, p.ToRows())
+}
+
+func Test_Determinant(t *testing.T) {
+	tests := []struct {
+		rows [][]int64
+		det  string
+	}{
+		{rows: [][]int64{{2, 3, 6, 2}, {5, 6, 1, 6}, {8, 3, 1, 1}, {1, 1, 1, 1}}, det: "-43"},
+		{rows: [][]int64{{0, 2}, {3, 4}}, det: "-6"},
+		{rows: [][]int64{{1, 2}, {2, 4}}, det: "0"},
+		{rows: [][]int64{{3, 2, -1, 4}, {2, 1, 5, 7}, {0, 5, 2, -6}, {-1, 2, 1, 0}}, det: "-418"},
+		{rows: [][]int64{}, det: "1"},
+	}
+	for _, tc := range tests {
+		m, _ := NewMatrixFromRows(tc.rows)
+		det, err := m.Determinant()
+		assert.NoError(t, err)
+		assert.Equal(t, tc.det, det.RatString())
+	}
+
+	f, _ := NewMatrixFromRows([][]float64{{0.5, 1}, {0.25, 3}})
+	det, err := f.Determinant()
+	assert.NoError(t, err)
+	assert.Equal(t, "5/4", det.RatString())
+
+	_, err = NewMatrix[int](2, 3).Determinant()
+	assert.Error(t, err)
+
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		f, _ = NewMatrixFromRows([][]float64{{1, v}, {2, 3}})
+		_, err = f.Determinant()
+		assert.Error(t, err)
+		_, err = f.Inverse()
+		assert.Error(t, err)
+	}
+}
+
+func Test_Inverse(t *testing.T) {
+	m, _ := NewMatrixFromRows([][]int64{{2, 1}, {7, 4}})
+	inv, err := m.Inverse()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"4", "-1"}, {"-7", "2"}}, [][]string{ratStrings(inv[0]), ratStrings(inv[1])})
+
+	m, _ = NewMatrixFromRows([][]int64{{1, 2, 3}, {0, 1, 4}, {5, 6, 0}})
+	inv, err = m.Inverse()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-24", "18", "5"}, ratStrings(inv[0]))
+
+	m, _ = NewMatrixFromRows([][]int64{{3, 0}, {0, 2}})
+	inv, err = m.Inverse()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1/3", "0"}, ratStrings(inv[0]))
+
+	m, _ = NewMatrixFromRows([][]int64{{1, 2}, {2, 4}})
+	_, err = m.Inverse()
+	assert.ErrorIs(t, err, ErrSingular)
+}
@@ -0,0 +1,181 @@
+package matrices
+
+import (
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// BitRow is a row of bits over GF(2), bit j is bit j%64 of word j/64
+type BitRow []uint64
+
+func NewBitRow(n int) BitRow {
+	return make(BitRow, (n+63)/64)
+}
+
+func (r BitRow) Get(j int) bool {
+	return r[j/64]&(1<<(j%64)) != 0
+}
+
+func (r BitRow) Set(j int) {
+	r[j/64] |= 1 << (j % 64)
+}
+
+func (r BitRow) Flip(j int) {
+	r[j/64] ^= 1 << (j % 64)
+}
+
+// Xor adds o to r in place
+func (r BitRow) Xor(o BitRow) {
+	for i := range o {
+		r[i] ^= o[i]
+	}
+}
+
+func (r BitRow) OnesCount() int {
+	count := 0
+	for _, w := range r {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Ones returns the indexes of the set bits in increasing order
+func (r BitRow) Ones() []int {
+	ones := make([]int, 0, r.OnesCount())
+	for i, w := range r {
+		for w != 0 {
+			ones = append(ones, i*64+bits.TrailingZeros64(w))
+			w &= w - 1
+		}
+	}
+	return ones
+}
+
+func (r BitRow) Clone() BitRow {
+	return append(BitRow{}, r...)
+}
+
+// GF2System is a system of linear equations Ax = b over GF(2), where addition is xor
+type GF2System struct {
+	variables int
+	rows      []BitRow // bits 0 to variables-1 hold a row of A, bit variables holds b
+}
+
+func NewGF2System(variables int) *GF2System {
+	return &GF2System{variables: variables}
+}
+
+// AddEquation adds the equation x[vars[0]] + x[vars[1]] + ... = rhs, a repeated variable cancels itself out
+func (s *GF2System) AddEquation(vars []int, rhs bool) {
+	row := NewBitRow(s.variables + 1)
+	for _, j := range vars {
+		row.Flip(j)
+	}
+	if rhs {
+		row.Set(s.variables)
+	}
+	s.rows = append(s.rows, row)
+}
+
+// GF2Solution describes the solutions of a GF2System, every solution is Particular plus a sum of Nullspace rows
+type GF2Solution struct {
+	Rank       int
+	Consistent bool
+	// Particular has every free variable 0, nil when the system isn't consistent
+	Particular BitRow
+	// Nullspace is a basis of the solutions of Ax = 0, one row per free variable
+	Nullspace []BitRow
+}
+
+// Solve performs Gauss-Jordan elimination on a copy of the system, a word at a time
+func (s *GF2System) Solve() *GF2Solution {
+	n := s.variables
+	rows := make([]BitRow, len(s.rows))
+	for i, row := range s.rows {
+		rows[i] = row.Clone()
+	}
+
+	pivots := make([]int, 0, n)
+	for j := 0; j < n && len(pivots) < len(rows); j++ {
+		r := len(pivots)
+		found := -1
+		for i := r; i < len(rows); i++ {
+			if rows[i].Get(j) {
+				found = i
+				break
+			}
+		}
+		if found == -1 {
+			continue
+		}
+		rows[r], rows[found] = rows[found], rows[r]
+		for i := range rows {
+			if i != r && rows[i].Get(j) {
+				rows[i].Xor(rows[r])
+			}
+		}
+		pivots = append(pivots, j)
+	}
+
+	sol := &GF2Solution{Rank: len(pivots), Consistent: true}
+	for _, row := range rows[len(pivots):] {
+		if row.Get(n) {
+			sol.Consistent = false
+		}
+	}
+
+	if sol.Consistent {
+		sol.Particular = NewBitRow(n)
+		for i, j := range pivots {
+			if rows[i].Get(n) {
+				sol.Particular.Set(j)
+			}
+		}
+	}
+
+	isPivot := make([]bool, n)
+	for _, j := range pivots {
+		isPivot[j] = true
+	}
+	for f := 0; f < n; f++ {
+		if isPivot[f] {
+			continue
+		}
+		v := NewBitRow(n)
+		v.Set(f)
+		for i, j := range pivots {
+			if rows[i].Get(f) {
+				v.Set(j)
+			}
+		}
+		sol.Nullspace = append(sol.Nullspace, v)
+	}
+
+	return sol
+}
+
+// MinimumWeight returns the solution with the fewest set bits, visiting all 2^len(Nullspace) solutions in Gray code
+// order so each step is a single xor. It returns an error rather than enumerate a nullspace of more than maxNullity
+// rows.
+func (sol *GF2Solution) MinimumWeight(maxNullity int) (BitRow, error) {
+	if !sol.Consistent {
+		return nil, ErrInfeasible
+	}
+	k := len(sol.Nullspace)
+	if k > maxNullity || k >= 64 {
+		return nil, errors.Errorf("nullspace has %d rows, more than %d to enumerate", k, maxNullity)
+	}
+
+	cur := sol.Particular.Clone()
+	best := cur.Clone()
+	bestWeight := best.OnesCount()
+	for i := uint64(1); i < 1<<k; i++ {
+		cur.Xor(sol.Nullspace[bits.TrailingZeros64(i)])
+		if w := cur.OnesCount(); w < bestWeight {
+			copy(best, cur)
+			bestWeight = w
+		}
+	}
+	return best, nil
+}
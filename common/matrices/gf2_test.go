@@ -0,0 +1,140 @@
+package matrices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BitRow(t *testing.T) {
+	r := NewBitRow(130)
+	assert.Len(t, r, 3)
+	r.Set(0)
+	r.Set(64)
+	r.Flip(129)
+	r.Flip(0)
+	assert.False(t, r.Get(0))
+	assert.True(t, r.Get(64))
+	assert.Equal(t, []int{64, 129}, r.Ones())
+
+	o := NewBitRow(130)
+	o.Set(64)
+	o.Set(3)
+	r.Xor(o)
+	assert.Equal(t, []int{3, 129}, r.Ones())
+	assert.Equal(t, 2, r.OnesCount())
+}
+
+// day10 example machines, one equation per light over the buttons that toggle it
+func Test_GF2System(t *testing.T) {
+	tests := []struct {
+		name      string
+		buttons   [][]int
+		diagram   string
+		rank      int
+		nullity   int
+		minWeight int
+	}{
+		{
+			name:      "example 1",
+			buttons:   [][]int{{3}, {1, 3}, {2}, {2, 3}, {0, 2}, {0, 1}},
+			diagram:   ".##.",
+			rank:      4,
+			nullity:   2,
+			minWeight: 2,
+		},
+		{
+			name:      "example 2",
+			buttons:   [][]int{{0, 2, 3, 4}, {2, 3}, {0, 4}, {0, 1, 2}, {1, 2, 3, 4}},
+			diagram:   "...#.",
+			rank:      4,
+			nullity:   1,
+			minWeight: 3,
+		},
+		{
+			name:      "example 3",
+			buttons:   [][]int{{0, 1, 2, 3, 4}, {0, 3, 4}, {0, 1, 2, 4, 5}, {1, 2}},
+			diagram:   ".###.#",
+			rank:      3,
+			nullity:   1,
+			minWeight: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lights := make([][]int, len(tc.diagram))
+			for b, button := range tc.buttons {
+				for _, l := range button {
+					lights[l] = append(lights[l], b)
+				}
+			}
+			s := NewGF2System(len(tc.buttons))
+			for l, vars := range lights {
+				s.AddEquation(vars, tc.diagram[l] == '#')
+			}
+
+			sol := s.Solve()
+			assert.True(t, sol.Consistent)
+			assert.Equal(t, tc.rank, sol.Rank)
+			assert.Len(t, sol.Nullspace, tc.nullity)
+
+			// every basis row toggles no light and the particular solution toggles the diagram
+			toggled := func(x BitRow) string {
+				state := []byte(tc.diagram)
+				for l := range state {
+					state[l] = '.'
+				}
+				for _, b := range x.Ones() {
+					for _, l := range tc.buttons[b] {
+						state[l] ^= '.' ^ '#'
+					}
+				}
+				return string(state)
+			}
+			for _, v := range sol.Nullspace {
+				assert.NotZero(t, v.OnesCount())
+				assert.NotContains(t, toggled(v), "#")
+			}
+			assert.Equal(t, tc.diagram, toggled(sol.Particular))
+
+			best, err := sol.MinimumWeight(10)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.minWeight, best.OnesCount())
+			assert.Equal(t, tc.diagram, toggled(best))
+		})
+	}
+}
+
+func Test_GF2SystemInconsistent(t *testing.T) {
+	s := NewGF2System(2)
+	s.AddEquation([]int{0, 1}, true)
+	s.AddEquation([]int{0}, false)
+	s.AddEquation([]int{1}, false)
+	sol := s.Solve()
+	assert.False(t, sol.Consistent)
+	assert.Nil(t, sol.Particular)
+
+	_, err := sol.MinimumWeight(10)
+	assert.ErrorIs(t, err, ErrInfeasible)
+}
+
+func Test_GF2SystemWide(t *testing.T) {
+	// x[i] + x[i+1] = 1 along a chain crossing word boundaries alternates the bits
+	n := 150
+	s := NewGF2System(n)
+	for i := 0; i+1 < n; i++ {
+		s.AddEquation([]int{i, i + 1}, true)
+	}
+	sol := s.Solve()
+	assert.Equal(t, n-1, sol.Rank)
+	assert.Len(t, sol.Nullspace, 1)
+	assert.Equal(t, n, sol.Nullspace[0].OnesCount())
+
+	best, err := sol.MinimumWeight(1)
+	assert.NoError(t, err)
+	assert.Equal(t, n/2, best.OnesCount())
+
+	_, err = sol.MinimumWeight(0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,284 @@
+package matrices
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// ToBigIntMatrix copies an integer matrix into big.Int
+func ToBigIntMatrix(A [][]int64) [][]*big.Int {
+	B := make([][]*big.Int, len(A))
+	for i := range A {
+		B[i] = make([]*big.Int, len(A[i]))
+		for j := range A[i] {
+			B[i][j] = big.NewInt(A[i][j])
+		}
+	}
+	return B
+}
+
+func identityBigInt(n int) [][]*big.Int {
+	I := make([][]*big.Int, n)
+	for i := range I {
+		I[i] = make([]*big.Int, n)
+		for j := range I[i] {
+			I[i][j] = new(big.Int)
+		}
+		I[i][i].SetInt64(1)
+	}
+	return I
+}
+
+// combineRows replaces rows r and i of M with a*R_r + b*R_i and c*R_r + d*R_i
+func combineRows(M [][]*big.Int, r, i int, a, b, c, d *big.Int) {
+	t := new(big.Int)
+	for k := range M[r] {
+		x, y := M[r][k], M[i][k]
+		nx := new(big.Int).Mul(a, x)
+		nx.Add(nx, t.Mul(b, y))
+		ny := new(big.Int).Mul(c, x)
+		ny.Add(ny, t.Mul(d, y))
+		M[r][k], M[i][k] = nx, ny
+	}
+}
+
+// combineColumns replaces columns r and i of M with a*C_r + b*C_i and c*C_r + d*C_i
+func combineColumns(M [][]*big.Int, r, i int, a, b, c, d *big.Int) {
+	t := new(big.Int)
+	for k := range M {
+		x, y := M[k][r], M[k][i]
+		nx := new(big.Int).Mul(a, x)
+		nx.Add(nx, t.Mul(b, y))
+		ny := new(big.Int).Mul(c, x)
+		ny.Add(ny, t.Mul(d, y))
+		M[k][r], M[k][i] = nx, ny
+	}
+}
+
+func negateRow(M [][]*big.Int, r int) {
+	for k := range M[r] {
+		M[r][k] = new(big.Int).Neg(M[r][k])
+	}
+}
+
+func swapColumns(M [][]*big.Int, a, b int) {
+	for k := range M {
+		M[k][a], M[k][b] = M[k][b], M[k][a]
+	}
+}
+
+// gcdCoefficients returns s, t, -y/g and x/g for g = gcd(x, y) = s*x + t*y. As a 2x2 matrix they have determinant
+// 1, so combining two rows or columns with them is unimodular and leaves g in place of x and 0 in place of y. When x
+// already divides y, s is 1 and t is 0 so x's row or column is left alone, which is what lets SmithNormalForm
+// terminate.
+func gcdCoefficients(x, y *big.Int) (s, t, c, d *big.Int) {
+	if q, r := new(big.Int).QuoRem(y, x, new(big.Int)); r.Sign() == 0 {
+		return big.NewInt(1), new(big.Int), q.Neg(q), big.NewInt(1)
+	}
+	s, t = new(big.Int), new(big.Int)
+	g := new(big.Int).GCD(s, t, x, y)
+	c = new(big.Int).Quo(y, g)
+	c.Neg(c)
+	d = new(big.Int).Quo(x, g)
+	return s, t, c, d
+}
+
+var (
+	bigZero = big.NewInt(0)
+	bigOne  = big.NewInt(1)
+)
+
+// HermiteNormalForm returns the row Hermite normal form H of A and a unimodular U with UA = H. H is in row echelon
+// form with positive pivots, and the entries above each pivot are reduced to 0 <= h < pivot.
+func HermiteNormalForm(A [][]int64) (H, U [][]*big.Int) {
+	H = ToBigIntMatrix(A)
+	m := len(H)
+	U = identityBigInt(m)
+	if m == 0 {
+		return H, U
+	}
+	n := len(H[0])
+
+	r := 0
+	for j := 0; j < n && r < m; j++ {
+		for i := r + 1; i < m; i++ {
+			if H[i][j].Sign() == 0 {
+				continue
+			}
+			if H[r][j].Sign() == 0 {
+				H[r], H[i] = H[i], H[r]
+				U[r], U[i] = U[i], U[r]
+				continue
+			}
+			s, t, c, d := gcdCoefficients(H[r][j], H[i][j])
+			combineRows(H, r, i, s, t, c, d)
+			combineRows(U, r, i, s, t, c, d)
+		}
+		if H[r][j].Sign() == 0 {
+			continue
+		}
+		if H[r][j].Sign() < 0 {
+			negateRow(H, r)
+			negateRow(U, r)
+		}
+		for i := 0; i < r; i++ {
+			q := new(big.Int).Div(H[i][j], H[r][j])
+			if q.Sign() != 0 {
+				q.Neg(q)
+				combineRows(H, i, r, bigOne, q, bigZero, bigOne)
+				combineRows(U, i, r, bigOne, q, bigZero, bigOne)
+			}
+		}
+		r++
+	}
+	return H, U
+}
+
+// SmithNormalForm returns the Smith normal form D of A and unimodular U and V with UAV = D. D is diagonal with
+// non-negative entries, and each diagonal entry divides the next.
+func SmithNormalForm(A [][]int64) (D, U, V [][]*big.Int) {
+	D = ToBigIntMatrix(A)
+	m := len(D)
+	U = identityBigInt(m)
+	if m == 0 {
+		return D, U, identityBigInt(0)
+	}
+	n := len(D[0])
+	V = identityBigInt(n)
+
+	for t := 0; t < m && t < n; t++ {
+		// move the smallest non zero entry left to (t, t)
+		pi, pj := -1, -1
+		for i := t; i < m; i++ {
+			for j := t; j < n; j++ {
+				if D[i][j].Sign() != 0 && (pi == -1 || D[i][j].CmpAbs(D[pi][pj]) < 0) {
+					pi, pj = i, j
+				}
+			}
+		}
+		if pi == -1 {
+			break
+		}
+		D[t], D[pi] = D[pi], D[t]
+		U[t], U[pi] = U[pi], U[t]
+		swapColumns(D, t, pj)
+		swapColumns(V, t, pj)
+
+		for {
+			// clearing the row can refill the column and the other way around, but the pivot only ever shrinks
+			for {
+				clean := true
+				for i := t + 1; i < m; i++ {
+					if D[i][t].Sign() != 0 {
+						s, u, c, d := gcdCoefficients(D[t][t], D[i][t])
+						combineRows(D, t, i, s, u, c, d)
+						combineRows(U, t, i, s, u, c, d)
+					}
+				}
+				for j := t + 1; j < n; j++ {
+					if D[t][j].Sign() != 0 {
+						clean = false
+						s, u, c, d := gcdCoefficients(D[t][t], D[t][j])
+						combineColumns(D, t, j, s, u, c, d)
+						combineColumns(V, t, j, s, u, c, d)
+					}
+				}
+				if clean {
+					break
+				}
+			}
+
+			// the pivot must divide every entry left, otherwise adding that entry's row brings in a smaller gcd
+			bad := -1
+			for i := t + 1; i < m && bad == -1; i++ {
+				for j := t + 1; j < n; j++ {
+					if new(big.Int).Rem(D[i][j], D[t][t]).Sign() != 0 {
+						bad = i
+						break
+					}
+				}
+			}
+			if bad == -1 {
+				break
+			}
+			combineRows(D, t, bad, bigOne, bigOne, bigZero, bigOne)
+			combineRows(U, t, bad, bigOne, bigOne, bigZero, bigOne)
+		}
+
+		if D[t][t].Sign() < 0 {
+			negateRow(D, t)
+			negateRow(U, t)
+		}
+	}
+	return D, U, V
+}
+
+// DiophantineSolution describes every integer solution of Ax = b as Particular plus an integer combination of the
+// Basis rows
+type DiophantineSolution struct {
+	Particular []*big.Int
+	Basis      [][]*big.Int
+}
+
+// SolveDiophantine finds the integer solutions of the augmented matrix A = [coefficients | b]. With UAV = D in Smith
+// normal form, Ax = b becomes Dy = Ub for x = Vy, which has an integer solution exactly when each diagonal entry of D
+// divides its entry of Ub and the rows of D that are zero have zero there too. The columns of V past the rank of D
+// span the solutions of Ax = 0. It returns an error wrapping ErrInfeasible when there is no integer solution.
+func SolveDiophantine(A [][]int64) (*DiophantineSolution, error) {
+	if len(A) == 0 || len(A[0]) == 0 {
+		return nil, errors.New("empty system")
+	}
+	m, n := len(A), len(A[0])-1
+
+	coefficients := make([][]int64, m)
+	for i, row := range A {
+		coefficients[i] = row[:n]
+	}
+	D, U, V := SmithNormalForm(coefficients)
+
+	c := make([]*big.Int, m)
+	for i := range c {
+		c[i] = new(big.Int)
+		for k := range A {
+			c[i].Add(c[i], new(big.Int).Mul(U[i][k], big.NewInt(A[k][n])))
+		}
+	}
+
+	rank := 0
+	for rank < m && rank < n && D[rank][rank].Sign() != 0 {
+		rank++
+	}
+
+	y := make([]*big.Int, n)
+	for i := range y {
+		y[i] = new(big.Int)
+	}
+	for i := 0; i < m; i++ {
+		if i < rank {
+			q, rem := new(big.Int).QuoRem(c[i], D[i][i], new(big.Int))
+			if rem.Sign() != 0 {
+				return nil, errors.Wrapf(ErrInfeasible, "%s is not a multiple of invariant factor %s", c[i], D[i][i])
+			}
+			y[i] = q
+		} else if c[i].Sign() != 0 {
+			return nil, errors.Wrap(ErrInfeasible, "system is inconsistent")
+		}
+	}
+
+	s := &DiophantineSolution{Particular: make([]*big.Int, n)}
+	for j := range s.Particular {
+		s.Particular[j] = new(big.Int)
+		for k := 0; k < rank; k++ {
+			s.Particular[j].Add(s.Particular[j], new(big.Int).Mul(V[j][k], y[k]))
+		}
+	}
+	for k := rank; k < n; k++ {
+		v := make([]*big.Int, n)
+		for j := range v {
+			v[j] = new(big.Int).Set(V[j][k])
+		}
+		s.Basis = append(s.Basis, v)
+	}
+	return s, nil
+}
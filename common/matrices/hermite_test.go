@@ -0,0 +1,156 @@
+package matrices
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mulBigInt(A, B [][]*big.Int) [][]*big.Int {
+	C := make([][]*big.Int, len(A))
+	for i := range A {
+		C[i] = make([]*big.Int, len(B[0]))
+		for j := range C[i] {
+			C[i][j] = new(big.Int)
+			for k := range B {
+				C[i][j].Add(C[i][j], new(big.Int).Mul(A[i][k], B[k][j]))
+			}
+		}
+	}
+	return C
+}
+
+func bigIntStrings(M [][]*big.Int) [][]string {
+	s := make([][]string, len(M))
+	for i, row := range M {
+		s[i] = make([]string, len(row))
+		for j, v := range row {
+			s[i][j] = v.String()
+		}
+	}
+	return s
+}
+
+var normalFormMatrices = [][][]int64{
+	{{2, 3, 6, 2}, {5, 6, 1, 6}, {8, 3, 1, 1}},
+	{{2, 4, 4}, {-6, 6, 12}, {10, -4, -16}},
+	{{0, 0, 3}, {0, 2, 0}, {0, 4, 6}},
+	{{1, 1, 1, 0}, {1, 0, 1, 1}, {1, 0, 1, 1}, {1, 1, 0, 0}, {1, 1, 1, 0}, {0, 0, 1, 0}},
+}
+
+func Test_HermiteNormalForm(t *testing.T) {
+	H, U := HermiteNormalForm([][]int64{{2, 3, 6, 2}, {5, 6, 1, 6}, {8, 3, 1, 1}})
+	assert.Equal(t, [][]string{{"1", "0", "50", "-11"}, {"0", "3", "28", "-2"}, {"0", "0", "61", "-13"}}, bigIntStrings(H))
+	assert.Equal(t, bigIntStrings(H), bigIntStrings(mulBigInt(U, ToBigIntMatrix([][]int64{{2, 3, 6, 2}, {5, 6, 1, 6}, {8, 3, 1, 1}}))))
+
+	for _, A := range normalFormMatrices {
+		H, U := HermiteNormalForm(A)
+		assert.Equal(t, bigIntStrings(H), bigIntStrings(mulBigInt(U, ToBigIntMatrix(A))))
+
+		// echelon form with positive pivots and reduced entries above them
+		lastPivot := -1
+		for i, row := range H {
+			pivot := -1
+			for j, v := range row {
+				if v.Sign() != 0 {
+					pivot = j
+					break
+				}
+			}
+			if pivot == -1 {
+				lastPivot = len(row)
+				continue
+			}
+			assert.Greater(t, pivot, lastPivot)
+			assert.Equal(t, 1, row[pivot].Sign())
+			for k := 0; k < i; k++ {
+				assert.True(t, H[k][pivot].Sign() >= 0 && H[k][pivot].Cmp(row[pivot]) < 0)
+			}
+			lastPivot = pivot
+		}
+	}
+}
+
+func Test_SmithNormalForm(t *testing.T) {
+	tests := []struct {
+		A        [][]int64
+		diagonal []string
+	}{
+		{A: normalFormMatrices[0], diagonal: []string{"1", "1", "1"}},
+		{A: normalFormMatrices[1], diagonal: []string{"2", "6", "12"}},
+		{A: normalFormMatrices[2], diagonal: []string{"1", "6", "0"}},
+		{A: normalFormMatrices[3], diagonal: []string{"1", "1", "1", "0"}},
+		{A: [][]int64{{4, 0}, {0, 6}}, diagonal: []string{"2", "12"}},
+	}
+
+	for _, tc := range tests {
+		D, U, V := SmithNormalForm(tc.A)
+		assert.Equal(t, bigIntStrings(D), bigIntStrings(mulBigInt(mulBigInt(U, ToBigIntMatrix(tc.A)), V)))
+
+		diagonal := make([]string, 0, len(tc.diagonal))
+		for i, row := range D {
+			for j, v := range row {
+				if i == j {
+					diagonal = append(diagonal, v.String())
+				} else {
+					assert.Zero(t, v.Sign())
+				}
+			}
+		}
+		assert.Equal(t, tc.diagonal, diagonal)
+	}
+}
+
+func Test_SolveDiophantine(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       [][]int64
+		nullity int
+		err     error
+	}{
+		{name: "single equation", A: [][]int64{{6, 10, 15, 1}}, nullity: 2},
+		{name: "unique", A: [][]int64{{2, 1, 5}, {1, -1, 1}}, nullity: 0},
+		{name: "redundant rows", A: [][]int64{{1, 1, 1, 0, 10}, {1, 0, 1, 1, 11}, {1, 0, 1, 1, 11}, {1, 1, 0, 0, 5}, {1, 1, 1, 0, 10}, {0, 0, 1, 0, 5}}, nullity: 1},
+		{name: "rational but not integer", A: [][]int64{{1, 1, 1}, {1, -1, 0}}, err: ErrInfeasible},
+		{name: "gcd doesn't divide", A: [][]int64{{4, 6, 3}}, err: ErrInfeasible},
+		{name: "inconsistent", A: [][]int64{{1, 2, 3}, {2, 4, 7}}, err: ErrInfeasible},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := SolveDiophantine(tc.A)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Len(t, s.Basis, tc.nullity)
+
+			n := len(tc.A[0]) - 1
+			apply := func(x []*big.Int) []string {
+				r := make([]string, len(tc.A))
+				for i, row := range tc.A {
+					sum := new(big.Int)
+					for j := 0; j < n; j++ {
+						sum.Add(sum, new(big.Int).Mul(big.NewInt(row[j]), x[j]))
+					}
+					r[i] = sum.String()
+				}
+				return r
+			}
+			b := make([]string, len(tc.A))
+			zero := make([]string, len(tc.A))
+			for i, row := range tc.A {
+				b[i] = big.NewInt(row[n]).String()
+				zero[i] = "0"
+			}
+			assert.Equal(t, b, apply(s.Particular))
+			for _, v := range s.Basis {
+				assert.Equal(t, zero, apply(v))
+			}
+		})
+	}
+}
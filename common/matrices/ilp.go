@@ -0,0 +1,222 @@
+package matrices
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInfeasible = errors.New("no feasible solution")
+	ErrUnbounded  = errors.New("objective is unbounded")
+	ErrOverflow   = errors.New("value overflows int64")
+	ErrNodeLimit  = errors.New("branch and bound node limit reached")
+)
+
+const noUpperBound = math.MaxInt64
+
+// DefaultMaxNodes is how many branch and bound nodes Minimize solves when MaxNodes isn't set
+const DefaultMaxNodes = 100000
+
+// IntegerProgram minimizes Objective·x over vectors x of non-negative integers. Constraint rows are augmented the
+// same way as the rows ToIntegerReducedEchelonForm takes, a row [a | b] means a·x = b in Equalities and a·x <= b in
+// Inequalities.
+type IntegerProgram struct {
+	Objective    []int64
+	Equalities   [][]int64
+	Inequalities [][]int64
+	MaxNodes     int // 0 means DefaultMaxNodes
+}
+
+// branch restricts each variable j of a branch and bound node to lo[j] <= x[j] <= hi[j]
+type branch struct {
+	lo []int64
+	hi []int64
+}
+
+// Minimize finds an x with the smallest objective value by branch and bound, solving the linear relaxation of each
+// branch exactly. It returns ErrInfeasible when the search shows no integer x satisfies the constraints, and
+// ErrUnbounded when a branch's relaxation has no minimum, which is also the integer program's answer whenever it has
+// any feasible x. Branching alone can't prove infeasibility when the feasible region is unbounded, so equalities with
+// no integer solution at all are ruled out up front, and any search that solves more than MaxNodes nodes, like a
+// program of inequalities only whose region is unbounded but holds no integer point, stops with ErrNodeLimit.
+func (p *IntegerProgram) Minimize() ([]int64, int64, error) {
+	n := len(p.Objective)
+	for _, row := range p.Equalities {
+		if len(row) != n+1 {
+			return nil, 0, errors.Errorf("equality row has %d columns, expected %d", len(row), n+1)
+		}
+	}
+	for _, row := range p.Inequalities {
+		if len(row) != n+1 {
+			return nil, 0, errors.Errorf("inequality row has %d columns, expected %d", len(row), n+1)
+		}
+	}
+	// branching can't prove there's no integer solution when the relaxation is unbounded, so rule it out up front
+	if len(p.Equalities) > 0 {
+		if _, err := SolveDiophantine(p.Equalities); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	root := branch{lo: make([]int64, n), hi: make([]int64, n)}
+	for j := range root.hi {
+		root.hi[j] = noUpperBound
+	}
+
+	maxNodes := p.MaxNodes
+	if maxNodes == 0 {
+		maxNodes = DefaultMaxNodes
+	}
+
+	var best []int64
+	var bestValue int64
+	stack := []branch{root}
+
+	for nodes := 0; len(stack) > 0; nodes++ {
+		if nodes == maxNodes {
+			return nil, 0, ErrNodeLimit
+		}
+		b := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		relaxation, err := p.relax(b)
+		if err == ErrInfeasible {
+			continue
+		} else if err != nil {
+			return nil, 0, err
+		}
+
+		// the objective has integer coefficients, so no integer x in this branch beats the rounded up relaxation
+		bound := new(big.Int).Neg(ratFloor(new(big.Rat).Neg(relaxation.Value)))
+		if best != nil && bound.Cmp(big.NewInt(bestValue)) >= 0 {
+			continue
+		}
+
+		j := mostFractional(relaxation.X)
+		if j == -1 {
+			candidate := make([]int64, n)
+			for i, v := range relaxation.X {
+				if !v.Num().IsInt64() {
+					return nil, 0, ErrOverflow
+				}
+				candidate[i] = v.Num().Int64()
+			}
+			value := dot(p.Objective, candidate)
+			if !value.IsInt64() {
+				return nil, 0, ErrOverflow
+			}
+			if v := value.Int64(); best == nil || v < bestValue {
+				best, bestValue = candidate, v
+			}
+			continue
+		}
+
+		floor := ratFloor(relaxation.X[j])
+		if !floor.IsInt64() || floor.Int64() == noUpperBound {
+			return nil, 0, ErrOverflow
+		}
+		up := branch{lo: append([]int64{}, b.lo...), hi: b.hi}
+		up.lo[j] = floor.Int64() + 1
+		down := branch{lo: b.lo, hi: append([]int64{}, b.hi...)}
+		down.hi[j] = floor.Int64()
+
+		// the down branch is explored first
+		stack = append(stack, up, down)
+	}
+
+	if best == nil {
+		return nil, 0, ErrInfeasible
+	}
+	return best, bestValue, nil
+}
+
+// relax solves the linear relaxation of b. The variables are shifted to y = x - lo so the relaxation keeps the
+// non-negative form a LinearProgram takes, and each finite upper bound becomes an inequality.
+func (p *IntegerProgram) relax(b branch) (*LPSolution, error) {
+	n := len(p.Objective)
+
+	// the shifted right hand side is b - a·lo, worked out exactly as it has to fit back in a LinearProgram row
+	shift := func(row []int64) ([]int64, error) {
+		rhs := new(big.Int).Sub(big.NewInt(row[n]), dot(row[:n], b.lo))
+		if !rhs.IsInt64() {
+			return nil, ErrOverflow
+		}
+		shifted := append([]int64{}, row...)
+		shifted[n] = rhs.Int64()
+		return shifted, nil
+	}
+
+	lp := &LinearProgram{Objective: p.Objective}
+	for _, row := range p.Equalities {
+		shifted, err := shift(row)
+		if err != nil {
+			return nil, err
+		}
+		lp.Equalities = append(lp.Equalities, shifted)
+	}
+	for _, row := range p.Inequalities {
+		shifted, err := shift(row)
+		if err != nil {
+			return nil, err
+		}
+		lp.Inequalities = append(lp.Inequalities, shifted)
+	}
+	for j := 0; j < n; j++ {
+		if b.hi[j] != noUpperBound {
+			if b.hi[j] < b.lo[j] {
+				return nil, ErrInfeasible
+			}
+			row := make([]int64, n+1)
+			row[j], row[n] = 1, b.hi[j]-b.lo[j]
+			lp.Inequalities = append(lp.Inequalities, row)
+		}
+	}
+
+	s := lp.SolveFast()
+	switch s.Status {
+	case Infeasible:
+		return nil, ErrInfeasible
+	case Unbounded:
+		return nil, ErrUnbounded
+	}
+	for j, v := range s.X {
+		v.Add(v, big.NewRat(b.lo[j], 1))
+	}
+	s.Value.Add(s.Value, new(big.Rat).SetInt(dot(p.Objective, b.lo)))
+	return s, nil
+}
+
+// mostFractional returns the index of the value whose fractional part is closest to 1/2, or -1 if they're all
+// integers
+func mostFractional(x []*big.Rat) int {
+	index := -1
+	var distance *big.Rat
+	half := big.NewRat(1, 2)
+	for j, v := range x {
+		if v.IsInt() {
+			continue
+		}
+		d := new(big.Rat).Sub(v, new(big.Rat).SetInt(ratFloor(v)))
+		d.Sub(d, half).Abs(d)
+		if index == -1 || d.Cmp(distance) < 0 {
+			index, distance = j, d
+		}
+	}
+	return index
+}
+
+// ratFloor returns the largest integer <= v
+func ratFloor(v *big.Rat) *big.Int {
+	return new(big.Int).Div(v.Num(), v.Denom()) // Euclidean division floors for the positive denominator
+}
+
+// dot returns a·b exactly
+func dot(a, b []int64) *big.Int {
+	sum, term := new(big.Int), new(big.Int)
+	for i := range a {
+		sum.Add(sum, term.Mul(big.NewInt(a[i]), big.NewInt(b[i])))
+	}
+	return sum
+}
@@ -0,0 +1,179 @@
+package matrices
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// satisfies checks x against every constraint of p exactly
+func satisfies(p *IntegerProgram, x []int64) bool {
+	n := len(x)
+	for _, v := range x {
+		if v < 0 {
+			return false
+		}
+	}
+	for _, row := range p.Equalities {
+		if dot(row[:n], x).Cmp(big.NewInt(row[n])) != 0 {
+			return false
+		}
+	}
+	for _, row := range p.Inequalities {
+		if dot(row[:n], x).Cmp(big.NewInt(row[n])) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func Test_IntegerProgramMinimize(t *testing.T) {
+	tests := []struct {
+		name    string
+		program IntegerProgram
+		value   int64
+		err     error
+	}{
+		{
+			name: "day10 example 1",
+			program: IntegerProgram{
+				Objective: []int64{1, 1, 1, 1, 1, 1},
+				Equalities: [][]int64{
+					{0, 0, 0, 0, 1, 1, 3},
+					{0, 1, 0, 0, 0, 1, 5},
+					{0, 0, 1, 1, 1, 0, 4},
+					{1, 1, 0, 1, 0, 0, 7},
+				},
+			},
+			value: 10,
+		},
+		{
+			name: "day10 example 2",
+			program: IntegerProgram{
+				Objective: []int64{1, 1, 1, 1, 1},
+				Equalities: [][]int64{
+					{1, 0, 1, 1, 0, 7},
+					{0, 0, 0, 1, 1, 5},
+					{1, 1, 0, 1, 1, 12},
+					{1, 1, 0, 0, 1, 7},
+					{1, 0, 1, 0, 1, 2},
+				},
+			},
+			value: 12,
+		},
+		{
+			name: "day10 example 3",
+			program: IntegerProgram{
+				Objective: []int64{1, 1, 1, 1},
+				Equalities: [][]int64{
+					{1, 1, 1, 0, 10},
+					{1, 0, 1, 1, 11},
+					{1, 0, 1, 1, 11},
+					{1, 1, 0, 0, 5},
+					{1, 1, 1, 0, 10},
+					{0, 0, 1, 0, 5},
+				},
+			},
+			value: 11,
+		},
+		{
+			name: "relaxation is fractional",
+			program: IntegerProgram{
+				Objective:    []int64{-1, -1},
+				Inequalities: [][]int64{{2, 2, 7}, {1, 0, 2}},
+			},
+			value: -3,
+		},
+		{
+			name: "knapsack",
+			program: IntegerProgram{
+				Objective:    []int64{-5, -4, -3},
+				Inequalities: [][]int64{{2, 3, 1, 5}, {4, 1, 2, 11}, {3, 4, 2, 8}},
+			},
+			value: -13,
+		},
+		{
+			name: "unconstrained variable stays at zero",
+			program: IntegerProgram{
+				Objective:  []int64{1, 2},
+				Equalities: [][]int64{{3, 0, 9}},
+			},
+			value: 3,
+		},
+		{
+			name: "no integer solution",
+			program: IntegerProgram{
+				Objective:  []int64{1, 1},
+				Equalities: [][]int64{{2, -2, 1}},
+			},
+			err: ErrInfeasible,
+		},
+		{
+			name: "no integer solution to the system",
+			program: IntegerProgram{
+				Objective:  []int64{1, 1, 0},
+				Equalities: [][]int64{{1, 1, 1, 1}, {1, -1, 1, 0}},
+			},
+			err: ErrInfeasible,
+		},
+		{
+			name: "no non-negative solution",
+			program: IntegerProgram{
+				Objective:  []int64{1, 1},
+				Equalities: [][]int64{{1, 1, -1}},
+			},
+			err: ErrInfeasible,
+		},
+		{
+			name: "integer hull is empty",
+			program: IntegerProgram{
+				Objective:    []int64{1},
+				Inequalities: [][]int64{{-3, -1}, {3, 2}},
+			},
+			err: ErrInfeasible,
+		},
+		{
+			name: "unbounded",
+			program: IntegerProgram{
+				Objective:  []int64{-1, 0},
+				Equalities: [][]int64{{1, -1, 0}},
+			},
+			err: ErrUnbounded,
+		},
+		{
+			// 2x - 2y = 1 has no integer solution, but every branch's relaxation has one further out
+			name: "unbounded region without integer points",
+			program: IntegerProgram{
+				Objective:    []int64{1, 1},
+				Inequalities: [][]int64{{2, -2, 1}, {-2, 2, -1}},
+				MaxNodes:     200,
+			},
+			err: ErrNodeLimit,
+		},
+		{
+			// branching up to x >= 2 shifts the second row's right hand side to 2^62 + 2^63
+			name: "shifted bound overflows",
+			program: IntegerProgram{
+				Objective:    []int64{-1},
+				Inequalities: [][]int64{{2, 3}, {-1 << 62, 1 << 62}},
+			},
+			err: ErrOverflow,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			x, value, err := tc.program.Minimize()
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.value, value)
+				assert.True(t, satisfies(&tc.program, x))
+				assert.Equal(t, value, dot(tc.program.Objective, x).Int64())
+			}
+		})
+	}
+}
@@ -6,76 +6,6 @@ import (
 
 const epsilon = 1e-9
 
-// gcd remains the same
-func gcd(a, b int64) int64 {
-	a = int64(math.Abs(float64(a)))
-	b = int64(math.Abs(float64(b)))
-	for b != 0 {
-		a, b = b, a%b
-	}
-	return a
-}
-
-// lcm remains the same
-func lcm(a, b int64) int64 {
-	if a == 0 || b == 0 {
-		return 0
-	}
-	res := (a / gcd(a, b)) * b
-	return int64(math.Abs(float64(res)))
-}
-
-// scaleToSmallestIntegers remains the same, using the more robust LCM approach
-func scaleToSmallestIntegers(A [][]float64) [][]int64 {
-	r := len(A)
-	if r == 0 {
-		return nil
-	}
-	c := len(A[0])
-	result := make([][]int64, r)
-
-	for i := 0; i < r; i++ {
-		currentLCM := int64(1)
-		const maxDenominator = 1000000
-
-		for j := 0; j < c; j++ {
-			val := A[i][j]
-			if math.Abs(val) < epsilon {
-				continue
-			}
-
-			f_val := math.Abs(val)
-			foundDenominator := int64(1)
-
-			for d := int64(1); d <= maxDenominator; d++ {
-				num := f_val * float64(d)
-				if math.Abs(num-math.Round(num)) < epsilon {
-					foundDenominator = d
-					break
-				}
-			}
-			currentLCM = lcm(currentLCM, foundDenominator)
-		}
-
-		result[i] = make([]int64, c)
-		sign := int64(1)
-		for j := 0; j < c; j++ {
-			if math.Abs(A[i][j]) >= epsilon {
-				if A[i][j] < 0 {
-					sign = int64(-1)
-				}
-				break
-			}
-		}
-
-		for j := 0; j < c; j++ {
-			scaledVal := math.Round(A[i][j] * float64(currentLCM) * float64(sign))
-			result[i][j] = int64(scaledVal)
-		}
-	}
-	return result
-}
-
 // ToFloatReducedEchelonForm performs Gauss-Jordan elimination on a matrix of float64.
 // FIX APPLIED: Improved elimination step to prevent floating-point accumulation errors.
 func ToFloatReducedEchelonForm(A [][]float64) [][]float64 {
@@ -143,27 +73,20 @@ func ToFloatReducedEchelonForm(A [][]float64) [][]float64 {
 	return A
 }
 
-// ToIntegerReducedEchelonForm is the final wrapper function
+// ToIntegerReducedEchelonForm reduces A exactly over the rationals, then scales each row to the smallest integers
 func ToIntegerReducedEchelonForm(A [][]int64) [][]int64 {
-
-	// 1. Convert initial integer matrix to float64
-	r := len(A)
-	c := len(A[0])
-	floatA := make([][]float64, r)
-	for i := 0; i < r; i++ {
-		floatA[i] = make([]float64, c)
-		for j := 0; j < c; j++ {
-			floatA[i][j] = float64(A[i][j])
-		}
+	if len(A) == 0 {
+		return nil
 	}
 
-	// 2. Reduce to RREF using float arithmetic
-	rrefFloat := ToFloatReducedEchelonForm(floatA)
+	rref := ToRationalReducedEchelonForm(ToRationalMatrix(A))
 
-	// 3. Scale back to the smallest integers
-	finalIntegerRREF := scaleToSmallestIntegers(rrefFloat)
+	result := make([][]int64, len(rref))
+	for i, row := range rref {
+		result[i] = ratRowToSmallestIntegers(row)
+	}
 
-	return finalIntegerRREF
+	return result
 }
 
 func FindFreeVariables(A [][]int64) []int {
@@ -0,0 +1,226 @@
+package matrices
+
+import (
+	"iter"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Affine is Constant + Coefficients[k] * x[FreeVariables[k]] summed over the free variables of a ParametricSolution
+type Affine struct {
+	Constant     *big.Rat
+	Coefficients []*big.Rat
+}
+
+// ParametricSolution gives every variable of a consistent system as an affine function of its free variables
+type ParametricSolution struct {
+	FreeVariables []int
+	Pivots        []int
+	// Variables holds an Affine per variable, a free variable's is just itself
+	Variables []Affine
+}
+
+// NewParametricSolution reads an augmented matrix in reduced row echelon form, such as ToIntegerReducedEchelonForm
+// returns, whose pivots may be any non zero value. It returns ErrInfeasible if a row reduces to 0 = b with b != 0.
+func NewParametricSolution(rref [][]int64) (*ParametricSolution, error) {
+	return NewRationalParametricSolution(ToRationalMatrix(rref))
+}
+
+// NewRationalParametricSolution is NewParametricSolution for a matrix of big.Rat
+func NewRationalParametricSolution(rref [][]*big.Rat) (*ParametricSolution, error) {
+	if len(rref) == 0 || len(rref[0]) == 0 {
+		return nil, errors.New("empty matrix")
+	}
+	n := len(rref[0]) - 1
+
+	pivotRows := make(map[int][]*big.Rat)
+	pivotRowIndexes := make(map[int]int)
+	ps := &ParametricSolution{}
+	for i, row := range rref {
+		pivot := -1
+		for j := 0; j < n; j++ {
+			if row[j].Sign() != 0 {
+				pivot = j
+				break
+			}
+		}
+		if pivot == -1 {
+			if row[n].Sign() != 0 {
+				return nil, ErrInfeasible
+			}
+			continue
+		}
+		if _, exists := pivotRows[pivot]; exists {
+			return nil, errors.Errorf("column %d has more than one pivot, matrix isn't in reduced row echelon form", pivot)
+		}
+		pivotRows[pivot] = row
+		pivotRowIndexes[pivot] = i
+		ps.Pivots = append(ps.Pivots, pivot)
+	}
+
+	for _, j := range ps.Pivots {
+		for i, row := range rref {
+			if i != pivotRowIndexes[j] && row[j].Sign() != 0 {
+				return nil, errors.Errorf("column %d has a pivot and a non zero value in row %d, matrix isn't in reduced row echelon form", j, i)
+			}
+		}
+	}
+
+	for j := 0; j < n; j++ {
+		if _, isPivot := pivotRows[j]; !isPivot {
+			ps.FreeVariables = append(ps.FreeVariables, j)
+		}
+	}
+
+	ps.Variables = make([]Affine, n)
+	for j := range ps.Variables {
+		a := Affine{Constant: new(big.Rat), Coefficients: make([]*big.Rat, len(ps.FreeVariables))}
+		for k, f := range ps.FreeVariables {
+			a.Coefficients[k] = new(big.Rat)
+			if f == j {
+				a.Coefficients[k].SetInt64(1)
+			}
+		}
+		// pivot * x[j] + sum row[f] * x[f] = b, so x[j] = b/pivot - sum row[f]/pivot * x[f]
+		if row, isPivot := pivotRows[j]; isPivot {
+			a.Constant.Quo(row[n], row[j])
+			for k, f := range ps.FreeVariables {
+				a.Coefficients[k].Quo(row[f], row[j])
+				a.Coefficients[k].Neg(a.Coefficients[k])
+			}
+		}
+		ps.Variables[j] = a
+	}
+
+	return ps, nil
+}
+
+// Eval returns the value of the affine function for the free variable values
+func (a Affine) Eval(free []int64) *big.Rat {
+	v := new(big.Rat).Set(a.Constant)
+	t := new(big.Rat)
+	for k, c := range a.Coefficients {
+		if c.Sign() != 0 {
+			v.Add(v, t.Mul(c, big.NewRat(free[k], 1)))
+		}
+	}
+	return v
+}
+
+// Format writes the function with x and the free variable indexes as names, like `3 - 1/2*x4 + x5`
+func (a Affine) Format(freeVariables []int) string {
+	var sb strings.Builder
+	if a.Constant.Sign() != 0 {
+		sb.WriteString(a.Constant.RatString())
+	}
+	for k, c := range a.Coefficients {
+		if c.Sign() == 0 {
+			continue
+		}
+		abs := new(big.Rat).Abs(c)
+		switch {
+		case sb.Len() == 0 && c.Sign() < 0:
+			sb.WriteString("-")
+		case sb.Len() > 0 && c.Sign() < 0:
+			sb.WriteString(" - ")
+		case sb.Len() > 0:
+			sb.WriteString(" + ")
+		}
+		if abs.Cmp(big.NewRat(1, 1)) != 0 {
+			sb.WriteString(abs.RatString())
+			sb.WriteString("*")
+		}
+		sb.WriteString("x")
+		sb.WriteString(strconv.Itoa(freeVariables[k]))
+	}
+	if sb.Len() == 0 {
+		return "0"
+	}
+	return sb.String()
+}
+
+// Eval returns every variable for the free variable values, which are ordered like FreeVariables
+func (ps *ParametricSolution) Eval(free []int64) []*big.Rat {
+	x := make([]*big.Rat, len(ps.Variables))
+	for j, a := range ps.Variables {
+		x[j] = a.Eval(free)
+	}
+	return x
+}
+
+func (ps *ParametricSolution) String() string {
+	lines := make([]string, 0, len(ps.Pivots))
+	for _, j := range ps.Pivots {
+		lines = append(lines, "x"+strconv.Itoa(j)+" = "+ps.Variables[j].Format(ps.FreeVariables))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// IntegerSolutions yields each integer solution with lo[j] <= x[j] <= hi[j] for every variable j, enumerating the
+// free variables over their bounds. A pivot variable is checked as soon as the free variables it depends on are
+// assigned, so infeasible prefixes are cut off early. The yielded slice is reused between iterations.
+func (ps *ParametricSolution) IntegerSolutions(lo, hi []int64) (iter.Seq[[]int64], error) {
+	if len(lo) != len(ps.Variables) || len(hi) != len(ps.Variables) {
+		return nil, errors.Errorf("expected bounds for %d variables", len(ps.Variables))
+	}
+	return func(yield func([]int64) bool) {
+
+		// checks[d] holds the pivots fully determined once the first d free variables are assigned
+		checks := make([][]int, len(ps.FreeVariables)+1)
+		for _, j := range ps.Pivots {
+			depth := 0
+			for k, c := range ps.Variables[j].Coefficients {
+				if c.Sign() != 0 {
+					depth = k + 1
+				}
+			}
+			checks[depth] = append(checks[depth], j)
+		}
+
+		free := make([]int64, len(ps.FreeVariables))
+		x := make([]int64, len(ps.Variables))
+
+		feasible := func(d int) bool {
+			for _, j := range checks[d] {
+				v := ps.Variables[j].Eval(free)
+				if !v.IsInt() || !v.Num().IsInt64() {
+					return false
+				}
+				x[j] = v.Num().Int64()
+				if x[j] < lo[j] || x[j] > hi[j] {
+					return false
+				}
+			}
+			return true
+		}
+
+		var assign func(d int) bool
+		assign = func(d int) bool {
+			if !feasible(d) {
+				return true
+			}
+			if d == len(free) {
+				return yield(x)
+			}
+			f := ps.FreeVariables[d]
+			if lo[f] > hi[f] {
+				return true
+			}
+			// stop at hi[f] rather than testing v <= hi[f], which v++ can't get past when hi[f] is math.MaxInt64
+			for v := lo[f]; ; v++ {
+				free[d], x[f] = v, v
+				if !assign(d + 1) {
+					return false
+				}
+				if v == hi[f] {
+					break
+				}
+			}
+			return true
+		}
+		assign(0)
+	}, nil
+}
@@ -0,0 +1,90 @@
+package matrices
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParametricSolution(t *testing.T) {
+	rref := ToIntegerReducedEchelonForm([][]int64{
+		{1, 1, 1, 0, 10},
+		{1, 0, 1, 1, 11},
+		{1, 0, 1, 1, 11},
+		{1, 1, 0, 0, 5},
+		{1, 1, 1, 0, 10},
+		{0, 0, 1, 0, 5},
+	})
+	ps, err := NewParametricSolution(rref)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, ps.Pivots)
+	assert.Equal(t, []int{3}, ps.FreeVariables)
+	assert.Equal(t, "x0 = 6 - x3\nx1 = -1 + x3\nx2 = 5", ps.String())
+	assert.Equal(t, []string{"2", "3", "5", "4"}, ratStrings(ps.Eval([]int64{4})))
+
+	lo := []int64{0, 0, 0, 0}
+	hi := []int64{10, 10, 10, 10}
+	var solutions [][]int64
+	seq, err := ps.IntegerSolutions(lo, hi)
+	assert.NoError(t, err)
+	for x := range seq {
+		solutions = append(solutions, slices.Clone(x))
+	}
+	assert.Equal(t, [][]int64{{5, 0, 5, 1}, {4, 1, 5, 2}, {3, 2, 5, 3}, {2, 3, 5, 4}, {1, 4, 5, 5}, {0, 5, 5, 6}}, solutions)
+
+	// stopping early
+	count := 0
+	for range seq {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, count)
+
+	_, err = ps.IntegerSolutions(lo[:1], hi)
+	assert.Error(t, err)
+}
+
+func Test_ParametricSolutionFractional(t *testing.T) {
+	// pivots that aren't 1 give fractional coefficients
+	ps, err := NewParametricSolution([][]int64{{2, 0, 1, 0, 7}, {0, 3, -1, 2, 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, "x0 = 7/2 - 1/2*x2\nx1 = 1/3 + 1/3*x2 - 2/3*x3", ps.String())
+
+	var solutions [][]int64
+	seq, err := ps.IntegerSolutions([]int64{0, 0, 0, 0}, []int64{5, 5, 5, 5})
+	assert.NoError(t, err)
+	for x := range seq {
+		solutions = append(solutions, slices.Clone(x))
+	}
+	// x2 must be odd for x0 to be an integer, and 1 + x2 - 2*x3 a non-negative multiple of 3 for x1
+	assert.Equal(t, [][]int64{{3, 0, 1, 1}, {2, 0, 3, 2}, {1, 2, 5, 0}, {1, 0, 5, 3}}, solutions)
+}
+
+func Test_ParametricSolutionInconsistent(t *testing.T) {
+	_, err := NewParametricSolution([][]int64{{1, 1, 2}, {0, 0, 1}})
+	assert.ErrorIs(t, err, ErrInfeasible)
+
+	_, err = NewParametricSolution([][]int64{{1, 1, 2}, {1, 0, 1}})
+	assert.Error(t, err)
+
+	// row echelon but not reduced, x0 would read as 2 instead of 1
+	_, err = NewParametricSolution([][]int64{{1, 1, 2}, {0, 1, 1}})
+	assert.Error(t, err)
+}
+
+func Test_ParametricSolutionMaxBound(t *testing.T) {
+	// x0 + x1 = MaxInt64, enumerating x1 up to MaxInt64 has to stop there rather than wrap around
+	ps, err := NewParametricSolution([][]int64{{1, 1, math.MaxInt64}})
+	assert.NoError(t, err)
+	seq, err := ps.IntegerSolutions([]int64{0, math.MaxInt64 - 2}, []int64{math.MaxInt64, math.MaxInt64})
+	assert.NoError(t, err)
+	var solutions [][]int64
+	for x := range seq {
+		solutions = append(solutions, slices.Clone(x))
+	}
+	assert.Equal(t, [][]int64{{2, math.MaxInt64 - 2}, {1, math.MaxInt64 - 1}, {0, math.MaxInt64}}, solutions)
+}
@@ -0,0 +1,155 @@
+package matrices
+
+import (
+	"math/big"
+)
+
+// ToRationalMatrix copies an integer matrix into exact rationals
+func ToRationalMatrix(A [][]int64) [][]*big.Rat {
+	R := make([][]*big.Rat, len(A))
+	for i := range A {
+		R[i] = make([]*big.Rat, len(A[i]))
+		for j := range A[i] {
+			R[i][j] = big.NewRat(A[i][j], 1)
+		}
+	}
+	return R
+}
+
+// ToRationalReducedEchelonForm performs Gauss-Jordan elimination on a matrix of big.Rat in place. The arithmetic
+// is exact, so no epsilon is needed to decide whether an entry is zero.
+func ToRationalReducedEchelonForm(A [][]*big.Rat) [][]*big.Rat {
+	r := len(A)
+	if r == 0 {
+		return nil
+	}
+	c := len(A[0])
+	pivotRow := 0
+
+	for j := 0; j < c && pivotRow < r; j++ {
+
+		// 1. Find any row with a non zero entry in column j
+		found := -1
+		for i := pivotRow; i < r; i++ {
+			if A[i][j].Sign() != 0 {
+				found = i
+				break
+			}
+		}
+		if found == -1 {
+			continue
+		}
+
+		// 2. Swap rows
+		A[pivotRow], A[found] = A[found], A[pivotRow]
+
+		// 3. Normalize: R_pivot = R_pivot / pivotVal
+		pivotVal := new(big.Rat).Set(A[pivotRow][j])
+		for k := j; k < c; k++ {
+			A[pivotRow][k] = new(big.Rat).Quo(A[pivotRow][k], pivotVal)
+		}
+
+		// 4. Eliminate above and below: R_i = R_i - A[i][j] * R_pivot
+		t := new(big.Rat)
+		for i := 0; i < r; i++ {
+			if i != pivotRow && A[i][j].Sign() != 0 {
+				factor := new(big.Rat).Set(A[i][j])
+				for k := j; k < c; k++ {
+					A[i][k] = new(big.Rat).Sub(A[i][k], t.Mul(factor, A[pivotRow][k]))
+				}
+			}
+		}
+
+		pivotRow++
+	}
+
+	return A
+}
+
+// RationalSolution describes the solutions of an augmented system Ax=b from its exact reduced row echelon form
+type RationalSolution struct {
+	// RREF is the reduced augmented matrix, the first Rank rows hold the pivots and the rest are zero
+	RREF [][]*big.Rat
+	// Pivots holds the pivot column of each of the first Rank rows
+	Pivots []int
+	Rank   int
+	// FreeVariables holds the variable columns without a pivot
+	FreeVariables []int
+	// Consistent is false when a row reduces to 0 = b with b != 0
+	Consistent bool
+	// Particular is a solution with every free variable 0, nil when the system isn't consistent
+	Particular []*big.Rat
+}
+
+// SolveRational reduces a copy of the augmented matrix A = [coefficients | b] exactly
+func SolveRational(A [][]int64) *RationalSolution {
+	return SolveRationalMatrix(ToRationalMatrix(A))
+}
+
+// SolveRationalMatrix reduces the augmented matrix A = [coefficients | b] in place
+func SolveRationalMatrix(A [][]*big.Rat) *RationalSolution {
+	s := &RationalSolution{Consistent: true}
+	if len(A) == 0 || len(A[0]) == 0 {
+		return s
+	}
+	s.RREF = ToRationalReducedEchelonForm(A)
+
+	n := len(A[0]) - 1
+	isPivot := make([]bool, n)
+	for _, row := range s.RREF {
+		pivot := -1
+		for j := 0; j < n; j++ {
+			if row[j].Sign() != 0 {
+				pivot = j
+				break
+			}
+		}
+		if pivot == -1 {
+			if row[n].Sign() != 0 {
+				s.Consistent = false
+			}
+			continue
+		}
+		isPivot[pivot] = true
+		s.Pivots = append(s.Pivots, pivot)
+	}
+	s.Rank = len(s.Pivots)
+
+	for j := 0; j < n; j++ {
+		if !isPivot[j] {
+			s.FreeVariables = append(s.FreeVariables, j)
+		}
+	}
+
+	if s.Consistent {
+		s.Particular = make([]*big.Rat, n)
+		for j := range s.Particular {
+			s.Particular[j] = new(big.Rat)
+		}
+		for i, pivot := range s.Pivots {
+			s.Particular[pivot] = new(big.Rat).Set(s.RREF[i][n])
+		}
+	}
+
+	return s
+}
+
+// ratRowToSmallestIntegers scales a row of rationals by the lcm of its denominators. The rows of an RREF have a
+// pivot of 1, so the scaled row has no common factor left and a positive leading entry.
+func ratRowToSmallestIntegers(row []*big.Rat) []int64 {
+	l := big.NewInt(1)
+	g := new(big.Int)
+	for _, v := range row {
+		d := v.Denom()
+		g.GCD(nil, nil, l, d)
+		l.Mul(l, new(big.Int).Quo(d, g))
+	}
+	result := make([]int64, len(row))
+	scaled := new(big.Int)
+	for j, v := range row {
+		scaled.Mul(v.Num(), l)
+		scaled.Quo(scaled, v.Denom())
+		result[j] = scaled.Int64()
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package matrices
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ratStrings(row []*big.Rat) []string {
+	s := make([]string, len(row))
+	for i, v := range row {
+		s[i] = v.RatString()
+	}
+	return s
+}
+
+func Test_SolveRational(t *testing.T) {
+	tests := []struct {
+		name          string
+		A             [][]int64
+		rank          int
+		pivots        []int
+		freeVariables []int
+		consistent    bool
+		particular    []string
+	}{
+		{
+			name:          "unique",
+			A:             [][]int64{{2, 1, 5}, {1, -1, 1}},
+			rank:          2,
+			pivots:        []int{0, 1},
+			freeVariables: nil,
+			consistent:    true,
+			particular:    []string{"2", "1"},
+		},
+		{
+			name:       "fractional",
+			A:          [][]int64{{3, 0, 1}, {0, 7, 2}},
+			rank:       2,
+			pivots:     []int{0, 1},
+			consistent: true,
+			particular: []string{"1/3", "2/7"},
+		},
+		{
+			name:          "free variable",
+			A:             [][]int64{{1, 1, 1, 0, 10}, {1, 0, 1, 1, 11}, {1, 0, 1, 1, 11}, {1, 1, 0, 0, 5}, {1, 1, 1, 0, 10}, {0, 0, 1, 0, 5}},
+			rank:          3,
+			pivots:        []int{0, 1, 2},
+			freeVariables: []int{3},
+			consistent:    true,
+			particular:    []string{"6", "-1", "5", "0"},
+		},
+		{
+			name:          "inconsistent",
+			A:             [][]int64{{1, 1, 2}, {2, 2, 5}},
+			rank:          1,
+			pivots:        []int{0},
+			freeVariables: []int{1},
+			consistent:    false,
+		},
+		{
+			name:          "zero column pivot skipped",
+			A:             [][]int64{{0, 2, 4}, {0, 1, 2}},
+			rank:          1,
+			pivots:        []int{1},
+			freeVariables: []int{0},
+			consistent:    true,
+			particular:    []string{"0", "2"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := SolveRational(tc.A)
+			assert.Equal(t, tc.rank, s.Rank)
+			assert.Equal(t, tc.pivots, s.Pivots)
+			assert.Equal(t, tc.freeVariables, s.FreeVariables)
+			assert.Equal(t, tc.consistent, s.Consistent)
+			if tc.consistent {
+				assert.Equal(t, tc.particular, ratStrings(s.Particular))
+			} else {
+				assert.Nil(t, s.Particular)
+			}
+		})
+	}
+}
+
+func Test_ToIntegerReducedEchelonForm(t *testing.T) {
+	A := [][]int64{
+		{0, 0, 1, 0, 1, 0, 1, 29},
+		{0, 0, 0, 0, 1, 1, 0, 33},
+		{0, 0, 0, 1, 1, 0, 0, 22},
+		{1, 1, 1, 0, 0, 0, 0, 34},
+		{1, 0, 0, 0, 1, 1, 0, 45},
+		{1, 0, 0, 0, 1, 0, 1, 35},
+	}
+	R := ToIntegerReducedEchelonForm(A)
+	assert.Equal(t, [][]int64{
+		{1, 0, 0, 0, 0, 0, 0, 12},
+		{0, 1, 0, 0, 0, 0, 0, 16},
+		{0, 0, 1, 0, 0, 0, 0, 6},
+		{0, 0, 0, 1, 0, 0, -1, -1},
+		{0, 0, 0, 0, 1, 0, 1, 23},
+		{0, 0, 0, 0, 0, 1, -1, 10},
+	}, R)
+
+	// rows with fractions scale up to the smallest integers
+	R = ToIntegerReducedEchelonForm([][]int64{{2, 1, 3}, {4, 2, 6}})
+	assert.Equal(t, [][]int64{{2, 1, 3}, {0, 0, 0}}, R)
+
+	R = ToIntegerReducedEchelonForm([][]int64{{3, 0, 2, 1}, {0, 2, 0, 3}})
+	assert.Equal(t, [][]int64{{3, 0, 2, 1}, {0, 2, 0, 3}}, R)
+}
@@ -0,0 +1,369 @@
+package matrices
+
+import (
+	"math"
+	"math/big"
+)
+
+type LPStatus int
+
+const (
+	Optimal LPStatus = iota
+	Infeasible
+	Unbounded
+)
+
+func (s LPStatus) String() string {
+	switch s {
+	case Optimal:
+		return "optimal"
+	case Infeasible:
+		return "infeasible"
+	case Unbounded:
+		return "unbounded"
+	}
+	return "unknown"
+}
+
+// LinearProgram minimizes Objective·x over vectors x of non-negative reals, constraint rows are augmented like the
+// rows of an IntegerProgram
+type LinearProgram struct {
+	Objective    []int64
+	Equalities   [][]int64
+	Inequalities [][]int64
+}
+
+// LPSolution holds an optimal vertex X and its objective Value when Status is Optimal
+type LPSolution struct {
+	Status LPStatus
+	X      []*big.Rat
+	Value  *big.Rat
+}
+
+// field is the arithmetic the simplex tableau is generic over
+type field[T any] interface {
+	fromInt(v int64) T
+	sub(a, b T) T
+	mul(a, b T) T
+	quo(a, b T) T
+	sign(a T) int
+}
+
+type ratField struct{}
+
+func (ratField) fromInt(v int64) *big.Rat   { return big.NewRat(v, 1) }
+func (ratField) sub(a, b *big.Rat) *big.Rat { return new(big.Rat).Sub(a, b) }
+func (ratField) mul(a, b *big.Rat) *big.Rat { return new(big.Rat).Mul(a, b) }
+func (ratField) quo(a, b *big.Rat) *big.Rat { return new(big.Rat).Quo(a, b) }
+func (ratField) sign(a *big.Rat) int        { return a.Sign() }
+
+// floatField treats values within epsilon of zero as zero
+type floatField struct{}
+
+func (floatField) fromInt(v int64) float64  { return float64(v) }
+func (floatField) sub(a, b float64) float64 { return a - b }
+func (floatField) mul(a, b float64) float64 { return a * b }
+func (floatField) quo(a, b float64) float64 { return a / b }
+func (floatField) sign(a float64) int {
+	switch {
+	case math.Abs(a) < epsilon:
+		return 0
+	case a < 0:
+		return -1
+	}
+	return 1
+}
+
+// tableau holds constraint rows whose last column is the right hand side, the basic column of each row, and the
+// reduced costs whose last column is minus the objective value
+type tableau[T any] struct {
+	f     field[T]
+	rows  [][]T
+	cost  []T
+	basis []int
+}
+
+func (t *tableau[T]) pivot(r, c int) {
+	pv := t.rows[r][c]
+	for k := range t.rows[r] {
+		t.rows[r][k] = t.f.quo(t.rows[r][k], pv)
+	}
+	eliminate := func(row []T) {
+		if factor := row[c]; t.f.sign(factor) != 0 {
+			for k := range row {
+				row[k] = t.f.sub(row[k], t.f.mul(factor, t.rows[r][k]))
+			}
+		}
+	}
+	for i := range t.rows {
+		if i != r {
+			eliminate(t.rows[i])
+		}
+	}
+	if t.cost != nil {
+		eliminate(t.cost)
+	}
+	t.basis[r] = c
+}
+
+// setCost prices out the basic columns of objective c
+func (t *tableau[T]) setCost(c []int64) {
+	width := len(t.rows[0])
+	t.cost = make([]T, width)
+	for j := range t.cost {
+		t.cost[j] = t.f.fromInt(0)
+	}
+	for j, v := range c {
+		t.cost[j] = t.f.fromInt(v)
+	}
+	for i, row := range t.rows {
+		if factor := t.cost[t.basis[i]]; t.f.sign(factor) != 0 {
+			for k := range row {
+				t.cost[k] = t.f.sub(t.cost[k], t.f.mul(factor, row[k]))
+			}
+		}
+	}
+}
+
+// minimize pivots with Bland's rule, which can't cycle, over the first columns entering columns. It returns false
+// if the objective is unbounded.
+func (t *tableau[T]) minimize(columns int) bool {
+	rhs := len(t.rows[0]) - 1
+	for {
+		enter := -1
+		for j := 0; j < columns; j++ {
+			if t.f.sign(t.cost[j]) < 0 {
+				enter = j
+				break
+			}
+		}
+		if enter == -1 {
+			return true
+		}
+
+		leave := -1
+		var best T
+		for i, row := range t.rows {
+			if t.f.sign(row[enter]) <= 0 {
+				continue
+			}
+			ratio := t.f.quo(row[rhs], row[enter])
+			if leave == -1 {
+				leave, best = i, ratio
+			} else if d := t.f.sign(t.f.sub(ratio, best)); d < 0 || (d == 0 && t.basis[i] < t.basis[leave]) {
+				leave, best = i, ratio
+			}
+		}
+		if leave == -1 {
+			return false
+		}
+		t.pivot(leave, enter)
+	}
+}
+
+// standardForm adds a slack column to each inequality and negates rows with a negative right hand side, returning
+// rows of A | b for Ax = b with x >= 0
+func (p *LinearProgram) standardForm() [][]int64 {
+	n := len(p.Objective)
+	slacks := len(p.Inequalities)
+	width := n + slacks + 1
+
+	A := make([][]int64, 0, len(p.Equalities)+slacks)
+	add := func(row []int64, slack int) {
+		r := make([]int64, width)
+		copy(r, row[:n])
+		if slack >= 0 {
+			r[n+slack] = 1
+		}
+		r[width-1] = row[n]
+		if r[width-1] < 0 {
+			for k := range r {
+				r[k] = -r[k]
+			}
+		}
+		A = append(A, r)
+	}
+	for _, row := range p.Equalities {
+		add(row, -1)
+	}
+	for i, row := range p.Inequalities {
+		add(row, i)
+	}
+	return A
+}
+
+// twoPhase solves min c·x for Ax = b, x >= 0 with A in standard form. Phase one minimizes the sum of an artificial
+// column per row to find a feasible basis, then drives the artificials out or drops their rows as redundant. Phase
+// two minimizes c from that basis.
+func twoPhase[T any](f field[T], A [][]int64, c []int64) (LPStatus, *tableau[T]) {
+	m := len(A)
+	if m == 0 {
+		for _, v := range c {
+			if v < 0 {
+				return Unbounded, nil
+			}
+		}
+		return Optimal, nil
+	}
+	N := len(A[0]) - 1
+
+	t := &tableau[T]{f: f, rows: make([][]T, m), basis: make([]int, m)}
+	for i, row := range A {
+		t.rows[i] = make([]T, N+m+1)
+		for j := 0; j < N; j++ {
+			t.rows[i][j] = f.fromInt(row[j])
+		}
+		for k := 0; k < m; k++ {
+			t.rows[i][N+k] = f.fromInt(0)
+		}
+		t.rows[i][N+i] = f.fromInt(1)
+		t.rows[i][N+m] = f.fromInt(row[N])
+		t.basis[i] = N + i
+	}
+
+	artificial := make([]int64, N+m)
+	for k := N; k < N+m; k++ {
+		artificial[k] = 1
+	}
+	t.setCost(artificial)
+	t.minimize(N + m)
+	if f.sign(t.cost[N+m]) != 0 {
+		return Infeasible, nil
+	}
+
+	for i := range t.rows {
+		if t.basis[i] >= N {
+			for j := 0; j < N; j++ {
+				if f.sign(t.rows[i][j]) != 0 {
+					t.pivot(i, j)
+					break
+				}
+			}
+		}
+	}
+
+	// an artificial still basic sits in a row that is zero over the real columns, so the row is redundant
+	rows := make([][]T, 0, m)
+	basis := make([]int, 0, m)
+	for i, row := range t.rows {
+		if t.basis[i] < N {
+			rows = append(rows, append(row[:N:N], row[N+m]))
+			basis = append(basis, t.basis[i])
+		}
+	}
+	t.rows, t.basis, t.cost = rows, basis, nil
+	if len(t.rows) == 0 {
+		return twoPhase(f, nil, c)
+	}
+
+	t.setCost(c)
+	if !t.minimize(N) {
+		return Unbounded, nil
+	}
+	return Optimal, t
+}
+
+// solution reads the vertex of an optimal tableau over the first n columns
+func solution[T any](t *tableau[T], n int, conv func(T) *big.Rat) []*big.Rat {
+	x := make([]*big.Rat, n)
+	for j := range x {
+		x[j] = new(big.Rat)
+	}
+	if t == nil {
+		return x
+	}
+	rhs := len(t.rows[0]) - 1
+	for i, j := range t.basis {
+		if j < n {
+			x[j] = conv(t.rows[i][rhs])
+		}
+	}
+	return x
+}
+
+func (p *LinearProgram) result(status LPStatus, x []*big.Rat) *LPSolution {
+	s := &LPSolution{Status: status}
+	if status == Optimal {
+		s.X = x
+		s.Value = new(big.Rat)
+		for j, v := range x {
+			s.Value.Add(s.Value, new(big.Rat).Mul(big.NewRat(p.Objective[j], 1), v))
+		}
+	}
+	return s
+}
+
+// Solve runs the two phase simplex method with exact big.Rat arithmetic
+func (p *LinearProgram) Solve() *LPSolution {
+	status, t := twoPhase[*big.Rat](ratField{}, p.standardForm(), p.Objective)
+	return p.result(status, solution(t, len(p.Objective), func(v *big.Rat) *big.Rat { return v }))
+}
+
+// SolveFast runs the simplex method in float64 and then checks the optimal basis it ends on with exact arithmetic,
+// falling back to Solve when the basis isn't exactly optimal or the float64 run finds no optimum
+func (p *LinearProgram) SolveFast() *LPSolution {
+	A := p.standardForm()
+	status, ft := twoPhase[float64](floatField{}, A, p.Objective)
+	if status != Optimal {
+		return p.Solve()
+	}
+	if ft == nil {
+		return p.result(Optimal, solution[float64](nil, len(p.Objective), nil))
+	}
+	if x := confirmBasis(A, p.Objective, ft.basis); x != nil {
+		return p.result(Optimal, x[:len(p.Objective)])
+	}
+	return p.Solve()
+}
+
+// confirmBasis pivots an exact tableau of A onto basis, returning its vertex if it's feasible and optimal for c
+func confirmBasis(A [][]int64, c []int64, basis []int) []*big.Rat {
+	f := ratField{}
+	N := len(A[0]) - 1
+	t := &tableau[*big.Rat]{f: f, rows: make([][]*big.Rat, len(A)), basis: make([]int, len(A))}
+	for i, row := range A {
+		t.rows[i] = make([]*big.Rat, N+1)
+		for j, v := range row {
+			t.rows[i][j] = f.fromInt(v)
+		}
+		t.basis[i] = -1
+	}
+
+	for _, j := range basis {
+		r := -1
+		for i := range t.rows {
+			if t.basis[i] == -1 && t.rows[i][j].Sign() != 0 {
+				r = i
+				break
+			}
+		}
+		if r == -1 {
+			return nil
+		}
+		t.pivot(r, j)
+	}
+
+	// rows without a basic column must have reduced to 0 = 0
+	rows := make([][]*big.Rat, 0, len(t.rows))
+	kept := make([]int, 0, len(t.rows))
+	for i, row := range t.rows {
+		if t.basis[i] != -1 {
+			if row[N].Sign() < 0 {
+				return nil
+			}
+			rows = append(rows, row)
+			kept = append(kept, t.basis[i])
+		} else if row[N].Sign() != 0 {
+			return nil
+		}
+	}
+	t.rows, t.basis = rows, kept
+
+	t.setCost(c)
+	for j := 0; j < N; j++ {
+		if t.cost[j].Sign() < 0 {
+			return nil
+		}
+	}
+	return solution(t, N, func(v *big.Rat) *big.Rat { return v })
+}
@@ -0,0 +1,103 @@
+package matrices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LinearProgramSolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		program LinearProgram
+		status  LPStatus
+		x       []string
+		value   string
+	}{
+		{
+			name: "fractional vertex",
+			program: LinearProgram{
+				Objective:    []int64{-1, -1},
+				Inequalities: [][]int64{{2, 1, 4}, {1, 2, 3}},
+			},
+			status: Optimal,
+			x:      []string{"5/3", "2/3"},
+			value:  "-7/3",
+		},
+		{
+			name: "equality with negative right hand side",
+			program: LinearProgram{
+				Objective:    []int64{2, 3},
+				Equalities:   [][]int64{{-1, -1, -4}},
+				Inequalities: [][]int64{{1, 0, 3}},
+			},
+			status: Optimal,
+			x:      []string{"3", "1"},
+			value:  "9",
+		},
+		{
+			name: "redundant equalities",
+			program: LinearProgram{
+				Objective:  []int64{1, 1, 1, 1},
+				Equalities: [][]int64{{1, 1, 1, 0, 10}, {1, 0, 1, 1, 11}, {1, 0, 1, 1, 11}, {1, 1, 0, 0, 5}, {1, 1, 1, 0, 10}, {0, 0, 1, 0, 5}},
+			},
+			status: Optimal,
+			x:      []string{"5", "0", "5", "1"},
+			value:  "11",
+		},
+		{
+			name: "degenerate",
+			program: LinearProgram{
+				Objective:    []int64{-10, 57, 9, 24},
+				Inequalities: [][]int64{{1, -11, -5, 18, 0}, {1, -3, -1, 2, 0}, {1, 0, 0, 0, 1}},
+			},
+			status: Optimal,
+			x:      []string{"1", "0", "1", "0"},
+			value:  "-1",
+		},
+		{
+			name: "infeasible",
+			program: LinearProgram{
+				Objective:    []int64{1, 1},
+				Equalities:   [][]int64{{1, 1, 5}},
+				Inequalities: [][]int64{{1, 1, 4}},
+			},
+			status: Infeasible,
+		},
+		{
+			name: "unbounded",
+			program: LinearProgram{
+				Objective:    []int64{-1, 1},
+				Inequalities: [][]int64{{-1, 1, 2}},
+			},
+			status: Unbounded,
+		},
+		{
+			name: "no constraints",
+			program: LinearProgram{
+				Objective: []int64{1, 0},
+			},
+			status: Optimal,
+			x:      []string{"0", "0"},
+			value:  "0",
+		},
+	}
+
+	for _, tc := range tests {
+		for name, solve := range map[string]func(*LinearProgram) *LPSolution{
+			"exact": (*LinearProgram).Solve,
+			"fast":  (*LinearProgram).SolveFast,
+		} {
+			t.Run(tc.name+" "+name, func(t *testing.T) {
+				s := solve(&tc.program)
+				assert.Equal(t, tc.status, s.Status)
+				if tc.status == Optimal {
+					assert.Equal(t, tc.x, ratStrings(s.X))
+					assert.Equal(t, tc.value, s.Value.RatString())
+				} else {
+					assert.Nil(t, s.X)
+				}
+			})
+		}
+	}
+}
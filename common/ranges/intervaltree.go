@@ -0,0 +1,166 @@
+package ranges
+
+import (
+	"iter"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Interval is a range from L to R carrying a Value
+type Interval[T Number, V any] struct {
+	L, R  T
+	Value V
+}
+
+type intervalNode[T Number] struct {
+	item int
+	// minL is the smallest left value of the intervals below the node, including its own
+	minL                T
+	parent, left, right *intervalNode[T]
+}
+
+// IntervalTree holds intervals of its Kind as given, without merging them, in a priority search tree. Each node holds
+// the interval with the largest right value in its subtree that isn't held higher up, and the rest are split into
+// halves ordered by their left values. Searching for values to the left of a query only goes down O(log n) paths, and
+// below those each node visited is either reported or stops the search, so queries cost O(log n + k) for k results.
+type IntervalTree[T Number, V any] struct {
+	kind      Kind
+	intervals []Interval[T, V]
+	nodes     []*intervalNode[T]
+	root      *intervalNode[T]
+	size      int
+}
+
+// NewIntervalTree builds a tree of a copy of intervals in O(n log n), they are identified by their index for Delete
+func NewIntervalTree[T Number, V any](kind Kind, intervals []Interval[T, V]) (*IntervalTree[T, V], error) {
+	intervals = append([]Interval[T, V](nil), intervals...)
+	t := &IntervalTree[T, V]{
+		kind:      kind,
+		intervals: intervals,
+		nodes:     make([]*intervalNode[T], len(intervals)),
+		size:      len(intervals),
+	}
+	order := make([]int, len(intervals))
+	for i, iv := range intervals {
+		if iv.R < iv.L {
+			return nil, errors.Errorf("interval %d: right value cannot be less than left value", i)
+		}
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		if intervals[order[a]].L == intervals[order[b]].L {
+			return intervals[order[a]].R < intervals[order[b]].R
+		}
+		return intervals[order[a]].L < intervals[order[b]].L
+	})
+	t.root = t.build(order, nil)
+	return t, nil
+}
+
+func (t *IntervalTree[T, V]) build(order []int, parent *intervalNode[T]) *intervalNode[T] {
+	if len(order) == 0 {
+		return nil
+	}
+	top := 0
+	for p, i := range order {
+		if t.intervals[i].R > t.intervals[order[top]].R {
+			top = p
+		}
+	}
+	n := &intervalNode[T]{item: order[top], parent: parent}
+	t.nodes[n.item] = n
+	rest := append(order[:top:top], order[top+1:]...)
+	n.left = t.build(rest[:len(rest)/2], n)
+	n.right = t.build(rest[len(rest)/2:], n)
+	t.span(n)
+	return n
+}
+
+// span updates the smallest left value below n from its interval and children
+func (t *IntervalTree[T, V]) span(n *intervalNode[T]) {
+	n.minL = t.intervals[n.item].L
+	for _, c := range []*intervalNode[T]{n.left, n.right} {
+		if c != nil {
+			n.minL = Min(n.minL, c.minL)
+		}
+	}
+}
+
+func (t *IntervalTree[T, V]) Kind() Kind {
+	return t.kind
+}
+
+// Len returns how many intervals haven't been deleted
+func (t *IntervalTree[T, V]) Len() int {
+	return t.size
+}
+
+// search yields the intervals below n that match, skipping the subtrees whose left values are all after the query and
+// the ones whose right values are all before it
+func (t *IntervalTree[T, V]) search(n *intervalNode[T], after, before func(v T) bool, match func(iv Interval[T, V]) bool, yield func(int, Interval[T, V]) bool) bool {
+	if n == nil || before(t.intervals[n.item].R) || after(n.minL) {
+		return true
+	}
+	if iv := t.intervals[n.item]; match(iv) && !yield(n.item, iv) {
+		return false
+	}
+	return t.search(n.left, after, before, match, yield) && t.search(n.right, after, before, match, yield)
+}
+
+// Containing yields the index and interval of each interval containing v
+func (t *IntervalTree[T, V]) Containing(v T) iter.Seq2[int, Interval[T, V]] {
+	return func(yield func(int, Interval[T, V]) bool) {
+		t.search(t.root,
+			func(l T) bool { return l > v || (t.kind == Open && l == v) },
+			func(r T) bool { return endsBefore(t.kind, r, v) },
+			func(iv Interval[T, V]) bool { return contains(t.kind, iv.L, iv.R, v) },
+			yield)
+	}
+}
+
+// Overlapping yields the index and interval of each interval sharing a value with the range from l to r
+func (t *IntervalTree[T, V]) Overlapping(l, r T) iter.Seq2[int, Interval[T, V]] {
+	return func(yield func(int, Interval[T, V]) bool) {
+		t.search(t.root,
+			func(il T) bool { return endsBefore(t.kind, r, il) },
+			func(ir T) bool { return endsBefore(t.kind, ir, l) },
+			func(iv Interval[T, V]) bool { return nonEmpty(t.kind, Max(l, iv.L), Min(r, iv.R)) },
+			yield)
+	}
+}
+
+// Delete removes the interval with index i in O(log n), returning false if it was already deleted
+func (t *IntervalTree[T, V]) Delete(i int) bool {
+	if i < 0 || i >= len(t.nodes) || t.nodes[i] == nil {
+		return false
+	}
+	n := t.nodes[i]
+	t.nodes[i] = nil
+	t.size--
+
+	// pull the interval with the larger right value of the children up, until a node without children is left empty
+	for n.left != nil || n.right != nil {
+		c := n.left
+		if c == nil || (n.right != nil && t.intervals[n.right.item].R > t.intervals[c.item].R) {
+			c = n.right
+		}
+		n.item = c.item
+		t.nodes[n.item] = n
+		n = c
+	}
+
+	p := n.parent
+	switch {
+	case p == nil:
+		t.root = nil
+	case p.left == n:
+		p.left = nil
+	default:
+		p.right = nil
+	}
+	for ; p != nil; p = p.parent {
+		t.span(p)
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package ranges
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectIndexes[V any](seq func(yield func(int, Interval[int, V]) bool)) []int {
+	indexes := []int{}
+	for i := range seq {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+func Test_IntervalTree(t *testing.T) {
+	intervals := []Interval[int, string]{
+		{L: 0, R: 10, Value: "a"},
+		{L: 5, R: 5, Value: "b"},
+		{L: 3, R: 7, Value: "c"},
+		{L: 0, R: 10, Value: "d"},
+		{L: 12, R: 20, Value: "e"},
+	}
+	tree, err := NewIntervalTree(Closed, intervals)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, tree.Len())
+
+	// the tree keeps its own copy
+	intervals[4] = Interval[int, string]{L: 30, R: 40, Value: "x"}
+
+	assert.Equal(t, []int{0, 1, 2, 3}, collectIndexes(tree.Containing(5)))
+	assert.Equal(t, []int{0, 3}, collectIndexes(tree.Containing(10)))
+	assert.Equal(t, []int{}, collectIndexes(tree.Containing(11)))
+	assert.Equal(t, []int{0, 3, 4}, collectIndexes(tree.Overlapping(8, 12)))
+
+	for _, iv := range tree.Containing(15) {
+		assert.Equal(t, "e", iv.Value)
+	}
+
+	assert.True(t, tree.Delete(0))
+	assert.False(t, tree.Delete(0))
+	assert.False(t, tree.Delete(7))
+	assert.Equal(t, []int{1, 2, 3}, collectIndexes(tree.Containing(5)))
+	for i := range intervals {
+		tree.Delete(i)
+	}
+	assert.Equal(t, 0, tree.Len())
+	assert.Equal(t, []int{}, collectIndexes(tree.Overlapping(0, 20)))
+
+	_, err = NewIntervalTree(Closed, []Interval[int, string]{{L: 2, R: 1}})
+	assert.Error(t, err)
+}
+
+func Test_IntervalTreeRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	intervals := make([]Interval[int, int], 500)
+	for i := range intervals {
+		l := rnd.Intn(1000)
+		intervals[i] = Interval[int, int]{L: l, R: l + rnd.Intn(50), Value: i}
+	}
+
+	for _, kind := range []Kind{Closed, HalfOpen, Open} {
+		tree, err := NewIntervalTree(kind, intervals)
+		assert.NoError(t, err)
+		deleted := make(map[int]bool)
+
+		for round := 0; round < 5; round++ {
+			for q := 0; q < 50; q++ {
+				v := rnd.Intn(1100) - 50
+				l, r := v, v+rnd.Intn(20)
+				containing, overlapping := []int{}, []int{}
+				for i, iv := range intervals {
+					if deleted[i] {
+						continue
+					}
+					if contains(kind, iv.L, iv.R, v) {
+						containing = append(containing, i)
+					}
+					if nonEmpty(kind, Max(l, iv.L), Min(r, iv.R)) {
+						overlapping = append(overlapping, i)
+					}
+				}
+				assert.Equal(t, containing, collectIndexes(tree.Containing(v)), "%s containing %d", kind, v)
+				assert.Equal(t, overlapping, collectIndexes(tree.Overlapping(l, r)), "%s overlapping %d %d", kind, l, r)
+			}
+			for d := 0; d < 80; d++ {
+				i := rnd.Intn(len(intervals))
+				assert.Equal(t, !deleted[i], tree.Delete(i))
+				deleted[i] = true
+			}
+			assert.Equal(t, len(intervals)-len(deleted), tree.Len())
+		}
+	}
+}
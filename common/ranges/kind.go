@@ -0,0 +1,139 @@
+package ranges
+
+import (
+	"math"
+)
+
+// Kind says which end values of its ranges a Collection includes. Values are treated as discrete: integers step by 1
+// and floats by the next representable value, so open (3, 4) holds nothing.
+type Kind int
+
+const (
+	// Closed ranges [l, r] include both end values, the zero value so collections are closed by default
+	Closed Kind = iota
+	// HalfOpen ranges [l, r) include l but not r
+	HalfOpen
+	// Open ranges (l, r) include neither end value
+	Open
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Closed:
+		return "closed"
+	case HalfOpen:
+		return "half-open"
+	case Open:
+		return "open"
+	}
+	return "unknown"
+}
+
+func isFloat[T Number]() bool {
+	var zero T
+	switch any(zero).(type) {
+	case float32, float64:
+		return true
+	}
+	return false
+}
+
+// next returns the smallest value greater than v
+func next[T Number](v T) T {
+	switch x := any(v).(type) {
+	case float32:
+		return T(math.Nextafter32(x, float32(math.Inf(1))))
+	case float64:
+		return T(math.Nextafter(x, math.Inf(1)))
+	}
+	return v + 1
+}
+
+// prev returns the largest value less than v
+func prev[T Number](v T) T {
+	switch x := any(v).(type) {
+	case float32:
+		return T(math.Nextafter32(x, float32(math.Inf(-1))))
+	case float64:
+		return T(math.Nextafter(x, math.Inf(-1)))
+	}
+	return v - 1
+}
+
+// nonEmpty returns true if the range from l to r holds any value
+func nonEmpty[T Number](k Kind, l, r T) bool {
+	switch k {
+	case HalfOpen:
+		return l < r
+	case Open:
+		return l < r && next(l) < r
+	}
+	return l <= r
+}
+
+// touches returns true if a range ending at r and a range starting at l >= its start share a value or, for half-open
+// ranges, meet, so they join into one. Closed ranges that are only adjacent, like [1, 2] and [3, 4], stay apart.
+func touches[T Number](k Kind, r, l T) bool {
+	switch k {
+	case HalfOpen:
+		return l <= r
+	case Open:
+		return l < r
+	}
+	return l <= r
+}
+
+// contains returns true if v is within the range from l to r
+func contains[T Number](k Kind, l, r, v T) bool {
+	switch k {
+	case HalfOpen:
+		return l <= v && v < r
+	case Open:
+		return l < v && v < r
+	}
+	return l <= v && v <= r
+}
+
+// endsBefore returns true if every value of a range ending at r is less than v
+func endsBefore[T Number](k Kind, r, v T) bool {
+	if k == Closed {
+		return r < v
+	}
+	return r <= v
+}
+
+// length returns how many integers are within the range from l to r, or its width for floats
+func length[T Number](k Kind, l, r T) T {
+	if isFloat[T]() {
+		return r - l
+	}
+	switch k {
+	case HalfOpen:
+		return r - l
+	case Open:
+		return r - l - 1
+	}
+	return r - l + 1
+}
+
+// cutBefore and cutAfter return the end values of what is left of a range before and after removing a range from a
+// to b
+func cutBefore[T Number](k Kind, a T) T {
+	switch k {
+	case HalfOpen:
+		return a
+	case Open:
+		return next(a)
+	}
+	return prev(a)
+}
+
+func cutAfter[T Number](k Kind, b T) T {
+	switch k {
+	case HalfOpen:
+		return b
+	case Open:
+		return prev(b)
+	}
+	return next(b)
+}
@@ -0,0 +1,91 @@
+package ranges
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_KindAdd(t *testing.T) {
+	testCases := []struct {
+		kind     Kind
+		pairs    []int
+		expected []int
+		length   int
+	}{
+		{kind: Closed, pairs: []int{0, 5, 5, 9, 10, 12}, expected: []int{0, 9, 10, 12}, length: 13},
+		{kind: Closed, pairs: []int{0, 2, 3, 4}, expected: []int{0, 2, 3, 4}, length: 5},
+		{kind: HalfOpen, pairs: []int{0, 5, 5, 9, 10, 12}, expected: []int{0, 9, 10, 12}, length: 11},
+		{kind: Open, pairs: []int{0, 5, 5, 9, 8, 12}, expected: []int{0, 5, 5, 12}, length: 4 + 6},
+		{kind: HalfOpen, pairs: []int{3, 3, 0, 2}, expected: []int{0, 2}, length: 2},
+		{kind: Open, pairs: []int{3, 4, 0, 2}, expected: []int{0, 2}, length: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.kind.String(), func(t *testing.T) {
+			c := NewCollection[int](tc.kind)
+			for i := 0; i < len(tc.pairs); i += 2 {
+				_, err := c.Add(tc.pairs[i], tc.pairs[i+1])
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, c.ValuePairs())
+			assert.Equal(t, tc.length, c.Len())
+
+			built, err := Build(tc.kind, tc.pairs)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, built.ValuePairs())
+		})
+	}
+}
+
+func Test_KindContains(t *testing.T) {
+	for kind, expected := range map[Kind][]bool{
+		Closed:   {false, true, true, true, false},
+		HalfOpen: {false, true, true, false, false},
+		Open:     {false, false, true, false, false},
+	} {
+		c, _ := Build(kind, []float64{1, 2, 4, 5})
+		for i, v := range []float64{0.5, 1, 1.5, 2, 3} {
+			assert.Equal(t, expected[i], c.Contains(v), "%s %v", kind, v)
+		}
+	}
+}
+
+func Test_KindSubtract(t *testing.T) {
+	half, _ := Build(HalfOpen, []float64{0, 10})
+	hole, _ := Build(HalfOpen, []float64{2.5, 5})
+	assert.Equal(t, []float64{0, 2.5, 5, 10}, half.Subtract(hole).ValuePairs())
+	assert.Equal(t, 7.5, half.Subtract(hole).Len())
+	assert.Equal(t, half.ValuePairs(), half.Subtract(hole).Union(hole).ValuePairs())
+
+	// closed floats step to the neighbouring representable values
+	closed, _ := Build(Closed, []float64{0, 10})
+	values, err := closed.Remove(5, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, math.Nextafter(5, 0), math.Nextafter(5, 10), 10}, values)
+	assert.False(t, closed.Contains(5))
+	_, _ = closed.Add(5, 5)
+	assert.True(t, closed.Contains(5))
+
+	open, _ := Build(Open, []int{0, 10})
+	remaining, err := open.Remove(2, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 3, 4, 10}, remaining)
+	assert.Equal(t, 7, open.Len())
+
+	complement, err := NewCollection[uint64](Closed).Complement(0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0, 3}, complement.ValuePairs())
+	complement, err = (&Collection[uint64]{values: []uint64{0, 1}}).Complement(0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3}, complement.ValuePairs())
+}
+
+func Test_KindOverlaps(t *testing.T) {
+	ranges := []float64{1, 3, 3, 5, 4.5, 6}
+	assert.Equal(t, []float64{3, 3, 4.5, 5}, KindOverlaps(Closed, ranges))
+	assert.Equal(t, []float64{4.5, 5}, KindOverlaps(HalfOpen, ranges))
+	assert.Equal(t, []float64{4.5, 5}, KindOverlaps(Open, ranges))
+	assert.Equal(t, 0.5, (&Collection[float64]{kind: HalfOpen, values: KindOverlaps(HalfOpen, ranges)}).Len())
+}
@@ -14,20 +14,10 @@ func Overlaps[T Number](ranges []T) []T {
 		return ranges
 	}
 
-	rs := make([][]T, len(ranges)/2)
-	for i, r := 0, 0; i < len(ranges); i, r = i+2, r+1 {
-		rs[r] = []T{ranges[i], ranges[i+1]}
-		if rs[r][1] < rs[r][0] {
-			rs[r][0], rs[r][1] = rs[r][1], rs[r][0]
-		}
-	}
+	rs := toPairs(ranges)
 
 	var overlaps []T
 
-	type OverlapRange[T Number] struct {
-		a, b T
-	}
-
 	rc := &Collection[T]{}
 
 	for i := 0; i < len(rs)-1; i++ {
@@ -52,6 +42,46 @@ func Overlaps[T Number](ranges []T) []T {
 	return overlaps
 }
 
+// KindOverlaps is Overlaps for ranges of kind, two ranges overlap if they share a value, so unlike Overlaps closed
+// ranges that only share an end value overlap there
+func KindOverlaps[T Number](kind Kind, ranges []T) []T {
+	if len(ranges)%2 != 0 {
+		return []T{}
+	}
+	if len(ranges) == 2 {
+		return ranges
+	}
+
+	rs := toPairs(ranges)
+
+	var overlaps []T
+
+	rc := NewCollection[T](kind)
+
+	for i := 0; i < len(rs)-1; i++ {
+		for j := i + 1; j < len(rs); j++ {
+			l, r := Max(rs[i][0], rs[j][0]), Min(rs[i][1], rs[j][1])
+			if nonEmpty(kind, l, r) {
+				overlaps, _ = rc.Add(l, r)
+			}
+		}
+	}
+
+	return overlaps
+}
+
+// toPairs splits ranges into [start, end] pairs, swapping any that are given end first
+func toPairs[T Number](ranges []T) [][]T {
+	rs := make([][]T, len(ranges)/2)
+	for i, r := 0, 0; i < len(ranges); i, r = i+2, r+1 {
+		rs[r] = []T{ranges[i], ranges[i+1]}
+		if rs[r][1] < rs[r][0] {
+			rs[r][0], rs[r][1] = rs[r][1], rs[r][0]
+		}
+	}
+	return rs
+}
+
 // Overlaps2 expects an even len array of multiple ranges (len 4 or more), and returns
 // an even length array of overlapping ranges with start and end index being the inclusive range boundary
 func Overlaps2[T Number](ranges []T) []T {
@@ -0,0 +1,197 @@
+package ranges
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseError reports where in a range list parsing failed
+type ParseError struct {
+	Msg    string
+	Line   int    // 1 based line of Token
+	Column int    // 1 based byte column of Token within its line
+	Token  string // the offending token, empty at the end of the list
+	Err    error  // underlying cause, if any
+}
+
+func (e *ParseError) Error() string {
+	token := e.Token
+	if token == "" {
+		token = "end of list"
+	} else {
+		token = fmt.Sprintf("%q", token)
+	}
+	msg := fmt.Sprintf("%s at line %d, column %d near %s", e.Msg, e.Line, e.Column, token)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func isSeparator(b byte) bool {
+	return b == ',' || b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+type listParser struct {
+	s     string
+	pos   int
+	line  int
+	start int // offset of the start of the line
+	float bool
+}
+
+func (p *listParser) errorf(offset int, token string, err error, format string, args ...any) *ParseError {
+	return &ParseError{Msg: fmt.Sprintf(format, args...), Line: p.line, Column: offset - p.start + 1, Token: token, Err: err}
+}
+
+func (p *listParser) skipSeparators() {
+	for p.pos < len(p.s) && isSeparator(p.s[p.pos]) {
+		if p.s[p.pos] == '\n' {
+			p.line++
+			p.start = p.pos + 1
+		}
+		p.pos++
+	}
+}
+
+// number scans a signed number, for floats with a fraction and exponent
+func (p *listParser) number() string {
+	begin := p.pos
+	if p.pos < len(p.s) && (p.s[p.pos] == '-' || p.s[p.pos] == '+') {
+		p.pos++
+	}
+	for p.pos < len(p.s) {
+		b := p.s[p.pos]
+		switch {
+		case b >= '0' && b <= '9':
+		case p.float && b == '.':
+		case p.float && (b == 'e' || b == 'E'):
+			if p.pos+1 < len(p.s) && (p.s[p.pos+1] == '-' || p.s[p.pos+1] == '+') {
+				p.pos++
+			}
+		default:
+			return p.s[begin:p.pos]
+		}
+		p.pos++
+	}
+	return p.s[begin:p.pos]
+}
+
+// token returns the text from offset up to the next separator, for error messages
+func (p *listParser) token(offset int) string {
+	end := offset
+	for end < len(p.s) && !isSeparator(p.s[end]) {
+		end++
+	}
+	return p.s[offset:end]
+}
+
+func parseNumber[T Number](s string) (T, error) {
+	var zero T
+	switch any(zero).(type) {
+	case int:
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		return T(v), err
+	case int32:
+		v, err := strconv.ParseInt(s, 10, 32)
+		return T(v), err
+	case int64:
+		v, err := strconv.ParseInt(s, 10, 64)
+		return T(v), err
+	case uint64:
+		v, err := strconv.ParseUint(s, 10, 64)
+		return T(v), err
+	case float32:
+		v, err := strconv.ParseFloat(s, 32)
+		return T(v), err
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return T(v), err
+}
+
+// Parse reads a list of ranges like "3-5,-10--2 7\n11-14" into value pairs [l0, r0, l1, r1, ...] in the order given.
+// Ranges are separated by any run of commas and whitespace, and a single value like 7 is the range 7-7.
+func Parse[T Number](s string) ([]T, error) {
+	p := &listParser{s: s, line: 1, float: isFloat[T]()}
+	var pairs []T
+
+	read := func() (T, error) {
+		offset := p.pos
+		n := p.number()
+		if n == "" || n == "-" || n == "+" {
+			return 0, p.errorf(offset, p.token(offset), nil, "expected a number")
+		}
+		v, err := parseNumber[T](n)
+		if err != nil {
+			return 0, p.errorf(offset, n, err, "invalid number")
+		}
+		return v, nil
+	}
+
+	for p.skipSeparators(); p.pos < len(p.s); p.skipSeparators() {
+		offset := p.pos
+		l, err := read()
+		if err != nil {
+			return nil, err
+		}
+		r := l
+		if p.pos < len(p.s) && p.s[p.pos] == '-' {
+			p.pos++
+			if r, err = read(); err != nil {
+				return nil, err
+			}
+		}
+		if p.pos < len(p.s) && !isSeparator(p.s[p.pos]) {
+			return nil, p.errorf(p.pos, p.token(p.pos), nil, "unexpected character")
+		}
+		if r < l {
+			return nil, p.errorf(offset, p.s[offset:p.pos], nil, "right value cannot be less than left value")
+		}
+		pairs = append(pairs, l, r)
+	}
+	return pairs, nil
+}
+
+// ParseCollection reads a list of ranges like Parse into a collection of kind
+func ParseCollection[T Number](kind Kind, s string) (*Collection[T], error) {
+	pairs, err := Parse[T](s)
+	if err != nil {
+		return nil, err
+	}
+	return Build(kind, pairs)
+}
+
+func formatNumber[T Number](v T) string {
+	switch x := any(v).(type) {
+	case float32:
+		return strconv.FormatFloat(float64(x), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	case uint64:
+		return strconv.FormatUint(x, 10)
+	}
+	return strconv.FormatInt(int64(v), 10)
+}
+
+// Format writes the ranges as "l-r" joined by sep, which Parse reads back
+func (c *Collection[T]) Format(sep string) string {
+	var sb strings.Builder
+	for i := 0; i < len(c.values); i += 2 {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(formatNumber(c.values[i]))
+		sb.WriteString("-")
+		sb.WriteString(formatNumber(c.values[i+1]))
+	}
+	return sb.String()
+}
+
+func (c *Collection[T]) String() string {
+	return c.Format(",")
+}
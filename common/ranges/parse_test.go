@@ -0,0 +1,79 @@
+package ranges
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []int
+	}{
+		{input: "11-22,95-115,998-1012", expected: []int{11, 22, 95, 115, 998, 1012}},
+		{input: "3-5\n10-14\n16-20\n12-18\n", expected: []int{3, 5, 10, 14, 16, 20, 12, 18}},
+		{input: " -10--2, -3-4\t7 \r\n", expected: []int{-10, -2, -3, 4, 7, 7}},
+		{input: "+1-+2", expected: []int{1, 2}},
+		{input: "", expected: nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			pairs, err := Parse[int](tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, pairs)
+		})
+	}
+
+	floats, err := Parse[float64]("0.05-1e-1,-1.5e2--2")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.05, 0.1, -150, -2}, floats)
+}
+
+func Test_ParseErrors(t *testing.T) {
+	testCases := []struct {
+		input  string
+		line   int
+		column int
+		token  string
+	}{
+		{input: "1-2,3-", line: 1, column: 7, token: ""},
+		{input: "1-2\n3-x4", line: 2, column: 3, token: "x4"},
+		{input: "1-2\n\n  5-3", line: 3, column: 3, token: "5-3"},
+		{input: "1-2a", line: 1, column: 4, token: "a"},
+		{input: "1.5-2", line: 1, column: 2, token: ".5-2"},
+		{input: "1 - 2", line: 1, column: 3, token: "-"},
+		{input: "99999999999999999999", line: 1, column: 1, token: "99999999999999999999"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Parse[int](tc.input)
+			var pe *ParseError
+			if assert.ErrorAs(t, err, &pe) {
+				assert.Equal(t, tc.line, pe.Line)
+				assert.Equal(t, tc.column, pe.Column)
+				assert.Equal(t, tc.token, pe.Token)
+			}
+		})
+	}
+
+	_, err := Parse[uint64]("-1-3")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid number at line 1, column 1")
+}
+
+func Test_Format(t *testing.T) {
+	c, err := ParseCollection[int64](Closed, "3-5\n10-14\n16-20\n12-18")
+	assert.NoError(t, err)
+	assert.Equal(t, "3-5,10-20", c.String())
+	assert.Equal(t, "3-5\n10-20", c.Format("\n"))
+
+	f, err := ParseCollection[float64](HalfOpen, "-2.5--1 0.1-1e-1 1e21-1e22")
+	assert.NoError(t, err)
+	assert.Equal(t, "-2.5--1,1e+21-1e+22", f.String())
+	parsed, err := ParseCollection[float64](HalfOpen, f.String())
+	assert.NoError(t, err)
+	assert.Equal(t, f.ValuePairs(), parsed.ValuePairs())
+
+	assert.Equal(t, "", NewCollection[int](Closed).String())
+}
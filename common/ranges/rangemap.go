@@ -0,0 +1,153 @@
+package ranges
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Mapping shifts the values of the range from L to R by Offset
+type Mapping[T Number] struct {
+	L, R   T
+	Offset T
+}
+
+// RangeMap maps values through disjoint ranges of its Kind, each with its own offset. Values outside every range map
+// to themselves if Identity is set, otherwise they have no image.
+type RangeMap[T Number] struct {
+	kind     Kind
+	Identity bool
+	mappings []Mapping[T]
+}
+
+func NewRangeMap[T Number](kind Kind, identity bool) *RangeMap[T] {
+	return &RangeMap[T]{kind: kind, Identity: identity}
+}
+
+func (m *RangeMap[T]) Kind() Kind {
+	return m.kind
+}
+
+// Mappings returns the mappings sorted by their left values
+func (m *RangeMap[T]) Mappings() []Mapping[T] {
+	return m.mappings
+}
+
+// Add maps the range from l to r by offset, it can't overlap a range already added. Uint ranges can be shifted down
+// by an offset that wraps around, like uint64(0) - 48.
+func (m *RangeMap[T]) Add(l, r, offset T) error {
+	if r < l {
+		return errors.New("right value cannot be less than left value")
+	}
+	if !nonEmpty(m.kind, l, r) {
+		return nil
+	}
+	i := sort.Search(len(m.mappings), func(k int) bool { return l < m.mappings[k].L })
+	if i > 0 && nonEmpty(m.kind, l, Min(r, m.mappings[i-1].R)) {
+		return errors.Errorf("range %v to %v overlaps %v to %v", l, r, m.mappings[i-1].L, m.mappings[i-1].R)
+	}
+	if i < len(m.mappings) && nonEmpty(m.kind, m.mappings[i].L, r) {
+		return errors.Errorf("range %v to %v overlaps %v to %v", l, r, m.mappings[i].L, m.mappings[i].R)
+	}
+	m.mappings = append(m.mappings, Mapping[T]{})
+	copy(m.mappings[i+1:], m.mappings[i:])
+	m.mappings[i] = Mapping[T]{L: l, R: r, Offset: offset}
+	return nil
+}
+
+// Map returns the image of v, found is false if v isn't mapped and the map isn't an identity outside its ranges
+func (m *RangeMap[T]) Map(v T) (image T, found bool) {
+	k := sort.Search(len(m.mappings), func(k int) bool { return !endsBefore(m.kind, m.mappings[k].R, v) })
+	if k < len(m.mappings) && contains(m.kind, m.mappings[k].L, m.mappings[k].R, v) {
+		return v + m.mappings[k].Offset, true
+	}
+	if m.Identity {
+		return v, true
+	}
+	return image, false
+}
+
+// domain returns the collection of the values m has a mapping for
+func (m *RangeMap[T]) domain() *Collection[T] {
+	pairs := make([]T, 0, 2*len(m.mappings))
+	for _, mapping := range m.mappings {
+		pairs = append(pairs, mapping.L, mapping.R)
+	}
+	return &Collection[T]{kind: m.kind, values: merge(m.kind, pairs)}
+}
+
+// Apply returns the image of every value of c, splitting its ranges where the mappings start and end
+func (m *RangeMap[T]) Apply(c *Collection[T]) (*Collection[T], error) {
+	if c.kind != m.kind {
+		return nil, errors.Errorf("can't apply a %s range map to a %s collection", m.kind, c.kind)
+	}
+	pairs := make([]T, 0, len(c.values)+2*len(m.mappings))
+	for i, j := 0, 0; i < len(c.values) && j < len(m.mappings); {
+		mapping := m.mappings[j]
+		l, r := Max(c.values[i], mapping.L), Min(c.values[i+1], mapping.R)
+		if nonEmpty(m.kind, l, r) {
+			pairs = append(pairs, l+mapping.Offset, r+mapping.Offset)
+		}
+		if c.values[i+1] < mapping.R {
+			i += 2
+		} else {
+			j++
+		}
+	}
+	if m.Identity {
+		pairs = append(pairs, c.Subtract(m.domain()).values...)
+	}
+	return &Collection[T]{kind: m.kind, values: merge(m.kind, pairs)}, nil
+}
+
+// Then returns the map of applying m and then o
+func (m *RangeMap[T]) Then(o *RangeMap[T]) (*RangeMap[T], error) {
+	if m.kind != o.kind {
+		return nil, errors.Errorf("can't compose a %s range map with a %s one", m.kind, o.kind)
+	}
+	composed := NewRangeMap[T](m.kind, m.Identity && o.Identity)
+	oDomain := o.domain()
+
+	// the image of each mapping of m is split by the mappings of o
+	for _, mm := range m.mappings {
+		image := &Collection[T]{kind: m.kind, values: []T{mm.L + mm.Offset, mm.R + mm.Offset}}
+		for _, om := range o.mappings {
+			for l, r := range image.Intersect(&Collection[T]{kind: m.kind, values: []T{om.L, om.R}}).Iter() {
+				composed.mappings = append(composed.mappings, Mapping[T]{L: l - mm.Offset, R: r - mm.Offset, Offset: mm.Offset + om.Offset})
+			}
+		}
+		if o.Identity {
+			for l, r := range image.Subtract(oDomain).Iter() {
+				composed.mappings = append(composed.mappings, Mapping[T]{L: l - mm.Offset, R: r - mm.Offset, Offset: mm.Offset})
+			}
+		}
+	}
+
+	// values m passes through unchanged only meet the mappings of o
+	if m.Identity {
+		passed := oDomain.Subtract(m.domain())
+		for _, om := range o.mappings {
+			for l, r := range passed.Intersect(&Collection[T]{kind: m.kind, values: []T{om.L, om.R}}).Iter() {
+				composed.mappings = append(composed.mappings, Mapping[T]{L: l, R: r, Offset: om.Offset})
+			}
+		}
+	}
+
+	sort.Slice(composed.mappings, func(a, b int) bool { return composed.mappings[a].L < composed.mappings[b].L })
+	return composed, nil
+}
+
+// Compose returns the map of applying maps in order
+func Compose[T Number](maps ...*RangeMap[T]) (*RangeMap[T], error) {
+	if len(maps) == 0 {
+		return nil, errors.New("no range maps to compose")
+	}
+	composed := maps[0]
+	for _, m := range maps[1:] {
+		var err error
+		if composed, err = composed.Then(m); err != nil {
+			return nil, err
+		}
+	}
+	return composed, nil
+}
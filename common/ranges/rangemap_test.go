@@ -0,0 +1,106 @@
+package ranges
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// almanac is the example of 2023 day 5, each map's lines are destination start, source start and length
+var almanac = [][][3]int64{
+	{{50, 98, 2}, {52, 50, 48}},
+	{{0, 15, 37}, {37, 52, 2}, {39, 0, 15}},
+	{{49, 53, 8}, {0, 11, 42}, {42, 0, 7}, {57, 7, 4}},
+	{{88, 18, 7}, {18, 25, 70}},
+	{{45, 77, 23}, {81, 45, 19}, {68, 64, 13}},
+	{{0, 69, 1}, {1, 0, 69}},
+	{{60, 56, 37}, {56, 93, 4}},
+}
+
+func almanacMaps(t *testing.T) []*RangeMap[int64] {
+	maps := make([]*RangeMap[int64], len(almanac))
+	for i, lines := range almanac {
+		maps[i] = NewRangeMap[int64](Closed, true)
+		for _, line := range lines {
+			assert.NoError(t, maps[i].Add(line[1], line[1]+line[2]-1, line[0]-line[1]))
+		}
+	}
+	return maps
+}
+
+func Test_RangeMapAlmanac(t *testing.T) {
+	maps := almanacMaps(t)
+	composed, err := Compose(maps...)
+	assert.NoError(t, err)
+
+	for seed, location := range map[int64]int64{79: 82, 14: 43, 55: 86, 13: 35} {
+		v := seed
+		for _, m := range maps {
+			v, _ = m.Map(v)
+		}
+		assert.Equal(t, location, v)
+		v, found := composed.Map(seed)
+		assert.True(t, found)
+		assert.Equal(t, location, v)
+	}
+
+	seeds, _ := Build(Closed, []int64{79, 79 + 14 - 1, 55, 55 + 13 - 1})
+	locations := seeds
+	for _, m := range maps {
+		locations, err = m.Apply(locations)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(46), locations.ValuePairs()[0])
+	assert.Equal(t, seeds.Len(), locations.Len())
+
+	composedLocations, err := composed.Apply(seeds)
+	assert.NoError(t, err)
+	assert.Equal(t, locations.ValuePairs(), composedLocations.ValuePairs())
+}
+
+func Test_RangeMap(t *testing.T) {
+	m := NewRangeMap[int](HalfOpen, false)
+	assert.NoError(t, m.Add(0, 10, 100))
+	assert.NoError(t, m.Add(10, 20, -10))
+	assert.Error(t, m.Add(15, 25, 0))
+	assert.Error(t, m.Add(-5, 1, 0))
+	assert.Error(t, m.Add(3, 2, 0))
+	assert.Equal(t, []Mapping[int]{{L: 0, R: 10, Offset: 100}, {L: 10, R: 20, Offset: -10}}, m.Mappings())
+
+	v, found := m.Map(10)
+	assert.True(t, found)
+	assert.Equal(t, 0, v)
+	_, found = m.Map(20)
+	assert.False(t, found)
+
+	c, _ := Build(HalfOpen, []int{-5, 15, 18, 30})
+	image, err := m.Apply(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 5, 8, 10, 100, 110}, image.ValuePairs())
+
+	m.Identity = true
+	image, err = m.Apply(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{-5, 5, 8, 10, 20, 30, 100, 110}, image.ValuePairs())
+
+	_, err = m.Apply(NewCollection[int](Closed))
+	assert.Error(t, err)
+
+	// without identity, values the first map passes through are dropped by the second
+	shift := NewRangeMap[int](HalfOpen, true)
+	assert.NoError(t, shift.Add(100, 110, -100))
+	for _, identity := range []bool{false, true} {
+		m.Identity = identity
+		composed, err := Compose(m, shift)
+		assert.NoError(t, err)
+		assert.Equal(t, identity, composed.Identity)
+		direct, _ := m.Apply(c)
+		direct, _ = shift.Apply(direct)
+		image, err = composed.Apply(c)
+		assert.NoError(t, err)
+		assert.Equal(t, direct.ValuePairs(), image.ValuePairs())
+	}
+
+	_, err = Compose[int]()
+	assert.Error(t, err)
+}
@@ -9,105 +9,105 @@ type Number interface {
 	int | int32 | float32 | int64 | uint64 | float64
 }
 
-type side int
-
-const (
-	leftSide side = iota
-	rightSide
-)
-
-type el[T Number] struct {
-	val T
-	s   side
-}
-
-type elements[T Number] []el[T]
-
-func (e elements[T]) Len() int {
-	return len(e)
-}
-
-func (e elements[T]) Less(a, b int) bool {
-	if e[a].val < e[b].val {
-		return true
-	}
-	if e[a].val == e[b].val {
-		if e[a].s == leftSide && e[b].s == rightSide {
-			return true
-		}
-	}
-	return false
+// Collection is a sorted list of disjoint ranges of its Kind, ranges that overlap or touch are joined
+type Collection[T Number] struct {
+	kind   Kind
+	values []T
 }
 
-func (e elements[T]) Swap(a, b int) {
-	e[a], e[b] = e[b], e[a]
+func NewCollection[T Number](kind Kind) *Collection[T] {
+	return &Collection[T]{kind: kind}
 }
 
-type Collection[T Number] struct {
-	values []T
+func (c *Collection[T]) Kind() Kind {
+	return c.kind
 }
 
 func (c *Collection[T]) ValuePairs() []T {
 	return c.values
 }
 
+// Len returns how many integers the ranges hold, or their total width for floats
 func (c *Collection[T]) Len() T {
 	var l T
 	for i := 0; i < len(c.values); i += 2 {
-		l += c.values[i+1] - c.values[i] + 1
+		l += length(c.kind, c.values[i], c.values[i+1])
 	}
 	return l
 }
 
+// Add inserts the range from l to r, joining it with the ranges it overlaps or touches. It finds them with a binary
+// search, so it doesn't sort again, but the later value pairs are still shifted so loading many ranges is faster with
+// Build. The returned values share the collection's storage. Adding a range that holds nothing, like [3, 3), is a no-op.
 func (c *Collection[T]) Add(l, r T) ([]T, error) {
 
 	if r < l {
 		return nil, errors.New("right value cannot be less than left value")
 	}
+	if !nonEmpty(c.kind, l, r) {
+		return c.values, nil
+	}
 
-	els := make(elements[T], 0, len(c.values)+2)
+	pairs := len(c.values) / 2
+	// the first range touching l, and the first starting after r without touching it
+	i := sort.Search(pairs, func(k int) bool { return touches(c.kind, c.values[2*k+1], l) })
+	j := sort.Search(pairs, func(k int) bool { return !touches(c.kind, r, c.values[2*k]) })
 
-	if len(c.values) > 0 {
-		for i, v := range c.values {
-			s := leftSide
-			if i%2 == 1 {
-				s = rightSide
-			}
-			els = append(els, el[T]{val: v, s: s})
-		}
+	if i < j {
+		l = Min(l, c.values[2*i])
+		r = Max(r, c.values[2*j-1])
 	}
 
-	els = append(els, el[T]{val: l, s: leftSide})
-	els = append(els, el[T]{val: r, s: rightSide})
-
-	sort.Sort(els)
-
-	values := make([]T, 0, len(c.values)+2)
-
-	leftValStack := make([]T, 0, 10)
-
-	for i := 0; i < len(els); i++ {
-		if els[i].s == leftSide {
-			leftValStack = append(leftValStack, els[i].val)
-		} else if els[i].s == rightSide {
-			leftVal := leftValStack[len(leftValStack)-1]
-			leftValStack = leftValStack[0 : len(leftValStack)-1]
-			if len(leftValStack) == 0 {
-				rightVal := els[i].val
-				if len(values) > 0 {
-					if leftVal == values[len(values)-1] {
-						values[len(values)-1] = rightVal
-						continue
-					}
-				}
-				values = append(values, leftVal)
-				values = append(values, rightVal)
-			}
+	// pairs [i, j) are replaced by the single pair [l, r], shifting the pairs after them into place
+	switch {
+	case i == j:
+		c.values = append(c.values, 0, 0)
+		copy(c.values[2*i+2:], c.values[2*i:])
+	case j-i > 1:
+		c.values = append(c.values[:2*i+2], c.values[2*j:]...)
+	}
+	c.values[2*i], c.values[2*i+1] = l, r
+
+	return c.values, nil
+}
+
+// Build creates a collection of kind from an even length list of range values [l0, r0, l1, r1, ...], sorting them once
+func Build[T Number](kind Kind, pairs []T) (*Collection[T], error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("expected an even number of values")
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		if pairs[i+1] < pairs[i] {
+			return nil, errors.Errorf("range %d: right value cannot be less than left value", i/2)
 		}
+	}
+	return &Collection[T]{kind: kind, values: merge(kind, pairs)}, nil
+}
 
+// Find returns the range containing v with a binary search
+func (c *Collection[T]) Find(v T) (l, r T, found bool) {
+	pairs := len(c.values) / 2
+	k := sort.Search(pairs, func(k int) bool { return !endsBefore(c.kind, c.values[2*k+1], v) })
+	if k < pairs && contains(c.kind, c.values[2*k], c.values[2*k+1], v) {
+		return c.values[2*k], c.values[2*k+1], true
 	}
+	return l, r, false
+}
 
-	c.values = values
+// merge sorts the ranges of pairs by their left values and joins the ones that overlap or touch into the canonical list
+// of value pairs, dropping the ones that hold nothing
+func merge[T Number](kind Kind, pairs []T) []T {
+	order := make([]int, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		if nonEmpty(kind, pairs[i], pairs[i+1]) {
+			order = append(order, i)
+		}
+	}
+	sort.Slice(order, func(a, b int) bool { return pairs[order[a]] < pairs[order[b]] })
 
-	return values, nil
+	values := make([]T, 0, 2*len(order))
+	for _, i := range order {
+		values = appendRange(kind, values, pairs[i], pairs[i+1])
+	}
+	return values
 }
@@ -0,0 +1,105 @@
+package ranges
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// Iter yields the left and right values of each range in increasing order
+func (c *Collection[T]) Iter() iter.Seq2[T, T] {
+	return func(yield func(l, r T) bool) {
+		for i := 0; i < len(c.values); i += 2 {
+			if !yield(c.values[i], c.values[i+1]) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns true if v is within one of the ranges
+func (c *Collection[T]) Contains(v T) bool {
+	_, _, found := c.Find(v)
+	return found
+}
+
+// Union returns a new collection of the values in either c or o. Like the other set operations it reads the ranges of
+// o as c's Kind.
+func (c *Collection[T]) Union(o *Collection[T]) *Collection[T] {
+	return &Collection[T]{kind: c.kind, values: merge(c.kind, append(slices.Clone(c.values), o.values...))}
+}
+
+// appendRange adds the range from l to r after the last range of values, joining them if they overlap or touch
+func appendRange[T Number](kind Kind, values []T, l, r T) []T {
+	if n := len(values); n > 0 && touches(kind, values[n-1], l) {
+		values[n-1] = Max(values[n-1], r)
+		return values
+	}
+	return append(values, l, r)
+}
+
+// Intersect returns a new collection of the values in both c and o
+func (c *Collection[T]) Intersect(o *Collection[T]) *Collection[T] {
+	values := make([]T, 0, len(c.values))
+	for i, j := 0, 0; i < len(c.values) && j < len(o.values); {
+		l := Max(c.values[i], o.values[j])
+		r := Min(c.values[i+1], o.values[j+1])
+		if nonEmpty(c.kind, l, r) {
+			values = appendRange(c.kind, values, l, r)
+		}
+		if c.values[i+1] < o.values[j+1] {
+			i += 2
+		} else {
+			j += 2
+		}
+	}
+	return &Collection[T]{kind: c.kind, values: values}
+}
+
+// Subtract returns a new collection of the values in c that aren't in o. The end values of what is left follow the
+// Kind, so removing [a, b] from closed [l, r] leaves [l, a-1] and [b+1, r], while half-open ranges leave [l, a) and
+// [b, r).
+func (c *Collection[T]) Subtract(o *Collection[T]) *Collection[T] {
+	values := make([]T, 0, len(c.values))
+	j := 0
+	for i := 0; i < len(c.values); i += 2 {
+		l, r := c.values[i], c.values[i+1]
+		// skip the ranges of o entirely left of this one, they can't overlap any later range of c either
+		for j < len(o.values) && !nonEmpty(c.kind, l, o.values[j+1]) {
+			j += 2
+		}
+		cut := false
+		for k := j; k < len(o.values) && nonEmpty(c.kind, o.values[k], r); k += 2 {
+			if o.values[k] > l {
+				values = appendRange(c.kind, values, l, cutBefore(c.kind, o.values[k]))
+			}
+			if o.values[k+1] >= r {
+				cut = true
+				break
+			}
+			l = cutAfter(c.kind, o.values[k+1])
+		}
+		if !cut {
+			values = appendRange(c.kind, values, l, r)
+		}
+	}
+	return &Collection[T]{kind: c.kind, values: values}
+}
+
+// Remove takes the range from l to r out of the collection, returning the new value pairs
+func (c *Collection[T]) Remove(l, r T) ([]T, error) {
+	if r < l {
+		return nil, errors.New("right value cannot be less than left value")
+	}
+	c.values = c.Subtract(&Collection[T]{kind: c.kind, values: merge(c.kind, []T{l, r})}).values
+	return c.values, nil
+}
+
+// Complement returns a new collection of the values within the range from lo to hi that aren't in c
+func (c *Collection[T]) Complement(lo, hi T) (*Collection[T], error) {
+	if hi < lo {
+		return nil, errors.New("right value cannot be less than left value")
+	}
+	return (&Collection[T]{kind: c.kind, values: merge(c.kind, []T{lo, hi})}).Subtract(c), nil
+}
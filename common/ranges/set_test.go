@@ -0,0 +1,208 @@
+package ranges
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectionOf[T Number](pairs ...T) *Collection[T] {
+	c := &Collection[T]{}
+	for i := 0; i < len(pairs); i += 2 {
+		_, _ = c.Add(pairs[i], pairs[i+1])
+	}
+	return c
+}
+
+func Test_SetOperations(t *testing.T) {
+	testCases := []struct {
+		name      string
+		a         []int
+		b         []int
+		union     []int
+		intersect []int
+		subtract  []int
+	}{
+		{
+			name:      "disjoint",
+			a:         []int{0, 5},
+			b:         []int{10, 15},
+			union:     []int{0, 5, 10, 15},
+			intersect: []int{},
+			subtract:  []int{0, 5},
+		},
+		{
+			name:      "overlapping",
+			a:         []int{0, 10},
+			b:         []int{5, 15},
+			union:     []int{0, 15},
+			intersect: []int{5, 10},
+			subtract:  []int{0, 4},
+		},
+		{
+			name:      "contained",
+			a:         []int{0, 20},
+			b:         []int{5, 8, 12, 14},
+			union:     []int{0, 20},
+			intersect: []int{5, 8, 12, 14},
+			subtract:  []int{0, 4, 9, 11, 15, 20},
+		},
+		{
+			name:      "touching end values",
+			a:         []int{0, 5},
+			b:         []int{5, 9},
+			union:     []int{0, 9},
+			intersect: []int{5, 5},
+			subtract:  []int{0, 4},
+		},
+		{
+			name:      "spanning several",
+			a:         []int{0, 3, 6, 9, 12, 15},
+			b:         []int{2, 13},
+			union:     []int{0, 15},
+			intersect: []int{2, 3, 6, 9, 12, 13},
+			subtract:  []int{0, 1, 14, 15},
+		},
+		{
+			name:      "removing everything",
+			a:         []int{3, 4, 8, 9},
+			b:         []int{-10, 10},
+			union:     []int{-10, 10},
+			intersect: []int{3, 4, 8, 9},
+			subtract:  []int{},
+		},
+		{
+			name:      "empty",
+			a:         []int{1, 2},
+			b:         []int{},
+			union:     []int{1, 2},
+			intersect: []int{},
+			subtract:  []int{1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := collectionOf(tc.a...), collectionOf(tc.b...)
+			assert.Equal(t, tc.union, a.Union(b).ValuePairs())
+			assert.Equal(t, tc.union, b.Union(a).ValuePairs())
+			assert.Equal(t, tc.intersect, a.Intersect(b).ValuePairs())
+			assert.Equal(t, tc.intersect, b.Intersect(a).ValuePairs())
+			assert.Equal(t, tc.subtract, a.Subtract(b).ValuePairs())
+
+			// the operations leave their operands alone
+			assert.Equal(t, collectionOf(tc.a...).ValuePairs(), a.ValuePairs())
+		})
+	}
+}
+
+func Test_Remove(t *testing.T) {
+	c := collectionOf[uint64](0, 10, 20, 30)
+
+	values, err := c.Remove(0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 10, 20, 30}, values)
+
+	values, err = c.Remove(5, 25)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 4, 26, 30}, values)
+
+	_, err = c.Remove(5, 4)
+	assert.Error(t, err)
+
+	assert.Equal(t, uint64(7), c.Len())
+}
+
+func Test_Complement(t *testing.T) {
+	c := collectionOf(3, 5, 8, 10)
+
+	complement, err := c.Complement(0, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 2, 6, 7, 11, 20}, complement.ValuePairs())
+	assert.Equal(t, 21, c.Len()+complement.Len())
+
+	complement, err = c.Complement(4, 9)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6, 7}, complement.ValuePairs())
+
+	_, err = c.Complement(1, 0)
+	assert.Error(t, err)
+}
+
+func Test_ContainsIter(t *testing.T) {
+	c := collectionOf(-5, -1, 3, 3, 10, 20)
+
+	for v, expected := range map[int]bool{-6: false, -5: true, -1: true, 0: false, 3: true, 4: false, 15: true, 21: false} {
+		assert.Equal(t, expected, c.Contains(v), "%d", v)
+	}
+
+	var pairs [][2]int
+	for l, r := range c.Iter() {
+		pairs = append(pairs, [2]int{l, r})
+	}
+	assert.Equal(t, [][2]int{{-5, -1}, {3, 3}, {10, 20}}, pairs)
+
+	for l := range c.Iter() {
+		assert.Equal(t, -5, l)
+		break
+	}
+}
+
+func Test_BuildFind(t *testing.T) {
+	pairs := []int{10, 20, -5, -1, 3, 3, 15, 25, 26, 30, 0, 2}
+	c, err := Build(Closed, pairs)
+	assert.NoError(t, err)
+	assert.Equal(t, collectionOf(pairs...).ValuePairs(), c.ValuePairs())
+	assert.Equal(t, []int{-5, -1, 0, 2, 3, 3, 10, 25, 26, 30}, c.ValuePairs())
+
+	l, r, found := c.Find(12)
+	assert.True(t, found)
+	assert.Equal(t, []int{10, 25}, []int{l, r})
+
+	for _, v := range []int{-6, 4, 9, 31} {
+		_, _, found = c.Find(v)
+		assert.False(t, found, "%d", v)
+	}
+
+	_, err = Build(Closed, []int{1, 2, 3})
+	assert.Error(t, err)
+	_, err = Build(Closed, []int{1, 2, 5, 4})
+	assert.Error(t, err)
+}
+
+func randomPairs(n int) []int64 {
+	rnd := rand.New(rand.NewSource(1))
+	pairs := make([]int64, 0, 2*n)
+	for i := 0; i < n; i++ {
+		l := rnd.Int63n(1_000_000_000)
+		pairs = append(pairs, l, l+rnd.Int63n(1000))
+	}
+	return pairs
+}
+
+func Benchmark_Add100k(b *testing.B) {
+	pairs := randomPairs(100_000)
+	for i := 0; i < b.N; i++ {
+		c := &Collection[int64]{}
+		for k := 0; k < len(pairs); k += 2 {
+			_, _ = c.Add(pairs[k], pairs[k+1])
+		}
+	}
+}
+
+func Benchmark_Build100k(b *testing.B) {
+	pairs := randomPairs(100_000)
+	for i := 0; i < b.N; i++ {
+		_, _ = Build(Closed, pairs)
+	}
+}
+
+func Benchmark_Contains100k(b *testing.B) {
+	c, _ := Build(Closed, randomPairs(100_000))
+	rnd := rand.New(rand.NewSource(2))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Contains(rnd.Int63n(1_000_000_000))
+	}
+}
@@ -3,8 +3,8 @@ package main
 import (
 	"fmt"
 	"github.com/mbordner/aoc2025/common/files"
+	"github.com/mbordner/aoc2025/common/ranges"
 	"strconv"
-	"strings"
 )
 
 func main() {
@@ -12,10 +12,13 @@ func main() {
 
 	invalidIds := make(map[string]bool)
 
-	for _, idRange := range strings.Split(string(idRanges), ",") {
-		tokens := strings.Split(idRange, "-")
-		start, _ := strconv.Atoi(strings.TrimSpace(tokens[0]))
-		end, _ := strconv.Atoi(strings.TrimSpace(tokens[1]))
+	pairs, err := ranges.Parse[int](string(idRanges))
+	if err != nil {
+		panic(err)
+	}
+
+	for r := 0; r < len(pairs); r += 2 {
+		start, end := pairs[r], pairs[r+1]
 
 		for i := start; i <= end; i++ {
 			strval := fmt.Sprintf("%d", i)
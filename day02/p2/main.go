@@ -7,8 +7,7 @@ package main
 import (
 	"fmt"
 	"github.com/mbordner/aoc2025/common/files"
-	"strconv"
-	"strings"
+	"github.com/mbordner/aoc2025/common/ranges"
 )
 
 func main() {
@@ -16,10 +15,13 @@ func main() {
 
 	sum := uint64(0)
 
-	for _, idRange := range strings.Split(string(idRanges), ",") {
-		tokens := strings.Split(idRange, "-")
-		start, _ := strconv.Atoi(strings.TrimSpace(tokens[0]))
-		end, _ := strconv.Atoi(strings.TrimSpace(tokens[1]))
+	pairs, err := ranges.Parse[int](string(idRanges))
+	if err != nil {
+		panic(err)
+	}
+
+	for r := 0; r < len(pairs); r += 2 {
+		start, end := pairs[r], pairs[r+1]
 
 		for i := start; i <= end; i++ {
 			strVal := fmt.Sprintf("%d", i)
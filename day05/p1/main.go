@@ -4,12 +4,8 @@ import (
 	"fmt"
 	"github.com/mbordner/aoc2025/common/files"
 	"github.com/mbordner/aoc2025/common/ranges"
-	"regexp"
 	"strconv"
-)
-
-var (
-	reRange = regexp.MustCompile(`^(\d+)-(\d+)$`)
+	"strings"
 )
 
 func main() {
@@ -17,14 +13,9 @@ func main() {
 
 	freshCount := 0
 
-	pairs := fresh.ValuePairs()
-
 	for _, ingredient := range ingredients {
-		for i := 0; i < len(pairs); i += 2 {
-			if ingredient >= pairs[i] && ingredient <= pairs[i+1] {
-				freshCount++
-				break
-			}
+		if fresh.Contains(ingredient) {
+			freshCount++
 		}
 	}
 
@@ -35,21 +26,19 @@ func main() {
 func getRangeCollection(filename string) (*ranges.Collection[int64], []int64) {
 	lines, _ := files.GetLines(filename)
 
-	valid := ranges.Collection[int64]{}
-
 	check := make([]int64, 0, 100)
 
+	var block []string
 	for i, line := range lines {
 		if line == "" {
-			lines = lines[i+1:]
+			block, lines = lines[:i], lines[i+1:]
 			break
 		}
-		matches := reRange.FindStringSubmatch(line)
-		if len(matches) == 3 {
-			l, _ := strconv.ParseInt(matches[1], 10, 64)
-			r, _ := strconv.ParseInt(matches[2], 10, 64)
-			valid.Add(l, r)
-		}
+	}
+
+	valid, err := ranges.ParseCollection[int64](ranges.Closed, strings.Join(block, "\n"))
+	if err != nil {
+		panic(err)
 	}
 
 	for _, line := range lines {
@@ -57,5 +46,5 @@ func getRangeCollection(filename string) (*ranges.Collection[int64], []int64) {
 		check = append(check, val)
 	}
 
-	return &valid, check
+	return valid, check
 }
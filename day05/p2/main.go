@@ -4,21 +4,23 @@ import (
 	"fmt"
 	"github.com/mbordner/aoc2025/common/files"
 	"github.com/mbordner/aoc2025/common/ranges"
-	"strconv"
 	"strings"
 )
 
 func main() {
-	intRanges := ranges.Collection[uint64]{}
+	lines := files.MustGetLines("../data.txt")
 
-	for _, line := range files.MustGetLines("../data.txt") {
-		if line == "" {
+	// the ranges are followed by a blank line and the ingredient ids
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines = lines[:i]
 			break
 		}
-		tokens := strings.Split(line, "-")
-		l, _ := strconv.ParseInt(tokens[0], 10, 64)
-		r, _ := strconv.ParseInt(tokens[1], 10, 64)
-		_, _ = intRanges.Add(uint64(l), uint64(r))
+	}
+
+	intRanges, err := ranges.ParseCollection[uint64](ranges.Closed, strings.Join(lines, "\n"))
+	if err != nil {
+		panic(err)
 	}
 
 	fmt.Println(intRanges.Len())
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"github.com/mbordner/aoc2025/common"
 	"github.com/mbordner/aoc2025/common/collection"
 	"github.com/mbordner/aoc2025/common/files"
 	"github.com/mbordner/aoc2025/common/geom"
@@ -14,17 +13,16 @@ import (
 func main() {
 	points := getPoints("../data.txt")
 
-	pairs := common.GetPairSets(points)
-	sort.Slice(pairs, func(i, j int) bool {
-		d1 := pairs[i][0].Distance(pairs[i][1])
-		d2 := pairs[j][0].Distance(pairs[j][1])
-		return d1 < d2
-	})
+	tree := geom.NewKDTree(points)
 
 	circuits := make([]*collection.Set[geom.Pos[int64]], 0, 10)
 
-	for i := 0; i < 1000; i++ {
-		pair := pairs[i]
+	count := 0
+	for i, j := range tree.Pairs() {
+		if count++; count > 1000 {
+			break
+		}
+		pair := []geom.Pos[int64]{points[i], points[j]}
 
 		inCircuits := make([]*collection.Set[geom.Pos[int64]], 0, 10)
 		outCircuits := make([]*collection.Set[geom.Pos[int64]], 0, 10)
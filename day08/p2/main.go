@@ -2,11 +2,9 @@ package main
 
 import (
 	"fmt"
-	"github.com/mbordner/aoc2025/common"
 	"github.com/mbordner/aoc2025/common/collection"
 	"github.com/mbordner/aoc2025/common/files"
 	"github.com/mbordner/aoc2025/common/geom"
-	"sort"
 	"strconv"
 	"strings"
 )
@@ -14,17 +12,12 @@ import (
 func main() {
 	points := getPoints("../data.txt")
 
-	pairs := common.GetPairSets(points)
-	sort.Slice(pairs, func(i, j int) bool {
-		d1 := pairs[i][0].Distance(pairs[i][1])
-		d2 := pairs[j][0].Distance(pairs[j][1])
-		return d1 < d2
-	})
+	tree := geom.NewKDTree(points)
 
 	circuits := make([]*collection.Set[geom.Pos[int64]], 0, 10)
 
-	for i := 0; i < len(pairs); i++ {
-		pair := pairs[i]
+	for i, j := range tree.Pairs() {
+		pair := []geom.Pos[int64]{points[i], points[j]}
 
 		inCircuits := make([]*collection.Set[geom.Pos[int64]], 0, 10)
 		outCircuits := make([]*collection.Set[geom.Pos[int64]], 0, 10)
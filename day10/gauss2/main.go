@@ -5,15 +5,10 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/alex-ant/gomath/gaussian-elimination"
-	"github.com/alex-ant/gomath/rational"
+	"github.com/mbordner/aoc2025/common/matrices"
 )
 
 func main() {
-	nr := func(i int64) rational.Rational {
-		return rational.New(i, 1)
-	}
-
 	text := `[0 0 1 0 1 0 1 29]
 [0 0 0 0 1 1 0 33]
 [0 0 0 1 1 0 0 22]
@@ -30,28 +25,31 @@ func main() {
 
 	replacer := strings.NewReplacer("[", "", "]", "")
 	lines := strings.Split(text, "\n")
-	equations := make([][]rational.Rational, len(lines))
+	equations := make([][]int64, len(lines))
 	for l, line := range lines {
 		line = replacer.Replace(line)
 		tokens := strings.Fields(line)
-		equations[l] = make([]rational.Rational, len(tokens))
+		equations[l] = make([]int64, len(tokens))
 		for i, t := range tokens {
 			val, _ := strconv.ParseInt(t, 10, 64)
-			equations[l][i] = nr(val)
+			equations[l][i] = val
 		}
 	}
 
-	res, gausErr := gaussian.SolveGaussian(equations, false)
-	if gausErr != nil {
-		log.Fatal(gausErr)
+	res := matrices.SolveRational(equations)
+	if !res.Consistent {
+		log.Fatal("no solution")
 	}
 
-	for _, v := range res {
-		log.Println(v)
+	log.Println("free variables:", res.FreeVariables)
+	for _, v := range res.Particular {
+		log.Println(v.RatString())
 	}
 	// Output:
-	// [{1 1}]
-	// [{2 1}]
-	// [{3 1}]
-	// [{4 1}]
+	// free variables: [3]
+	// 6
+	// -1
+	// 5
+	// 0
+	// every solution is x0 = 6 - x3, x1 = -1 + x3, x2 = 5, e.g. [2 3 5 4] with x3 = 4
 }
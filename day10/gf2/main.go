@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/mbordner/aoc2025/common"
+	"github.com/mbordner/aoc2025/common/files"
+	"github.com/mbordner/aoc2025/common/matrices"
+)
+
+var (
+	reLine   = regexp.MustCompile(`^\[([.#]+)\]\s*((?:\([\d|,]+\)\s*)+)\{([\d|,]+)\}\s*$`)
+	reButton = regexp.MustCompile(`\(([\d|,]+)\)`)
+)
+
+// part 1 as a linear system over GF(2): pressing a button twice undoes it, so each button is pressed 0 or 1 times
+// and each light must be toggled an odd number of times exactly when it's on in the diagram
+func main() {
+	lines := files.MustGetLines("../data.txt")
+
+	sum := 0
+	for _, line := range lines {
+		matches := reLine.FindStringSubmatch(line)
+		if len(matches) != 4 {
+			continue
+		}
+		diagram := matches[1]
+		var buttons [][]int
+		for _, match := range reButton.FindAllStringSubmatch(matches[2], -1) {
+			buttons = append(buttons, common.IntVals[int](match[1]))
+		}
+
+		// one equation per light, over the buttons that toggle it
+		lights := make([][]int, len(diagram))
+		for b, button := range buttons {
+			for _, l := range button {
+				lights[l] = append(lights[l], b)
+			}
+		}
+		system := matrices.NewGF2System(len(buttons))
+		for l, vars := range lights {
+			system.AddEquation(vars, diagram[l] == '#')
+		}
+
+		presses, err := system.Solve().MinimumWeight(20)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(diagram, "takes", presses.OnesCount(), presses.Ones())
+		sum += presses.OnesCount()
+	}
+
+	fmt.Println(sum)
+}
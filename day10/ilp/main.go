@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/mbordner/aoc2025/common"
+	"github.com/mbordner/aoc2025/common/files"
+	"github.com/mbordner/aoc2025/common/matrices"
+)
+
+var (
+	reLine   = regexp.MustCompile(`^\[([.#]+)\]\s*((?:\([\d|,]+\)\s*)+)\{([\d|,]+)\}\s*$`)
+	reButton = regexp.MustCompile(`\(([\d|,]+)\)`)
+)
+
+// part 2 as an integer program: minimize the sum of presses where each counter's buttons add up to its jolts
+func main() {
+	machines := getData("../data.txt")
+
+	sum := int64(0)
+	for i, m := range machines {
+		presses, total, err := m.program().Minimize()
+		if err != nil {
+			panic(fmt.Sprintf("machine %d: %v", i, err))
+		}
+		fmt.Println(m.jolts, "takes", total, presses)
+		sum += total
+	}
+
+	fmt.Println(sum)
+}
+
+type Machine struct {
+	buttons [][]int
+	jolts   []int64
+}
+
+func (m *Machine) program() *matrices.IntegerProgram {
+	p := &matrices.IntegerProgram{
+		Objective:  make([]int64, len(m.buttons)),
+		Equalities: make([][]int64, len(m.jolts)),
+	}
+	for b := range m.buttons {
+		p.Objective[b] = 1
+	}
+	for j, jolt := range m.jolts {
+		row := make([]int64, len(m.buttons)+1)
+		for b, button := range m.buttons {
+			if slices.Contains(button, j) {
+				row[b] = 1
+			}
+		}
+		row[len(m.buttons)] = jolt
+		p.Equalities[j] = row
+	}
+	return p
+}
+
+func getData(filename string) []*Machine {
+	lines := files.MustGetLines(filename)
+
+	machines := make([]*Machine, 0, len(lines))
+	for _, line := range lines {
+		matches := reLine.FindStringSubmatch(line)
+		if len(matches) == 4 {
+			m := &Machine{jolts: common.IntVals[int64](matches[3])}
+			for _, match := range reButton.FindAllStringSubmatch(matches[2], -1) {
+				m.buttons = append(m.buttons, common.IntVals[int](match[1]))
+			}
+			machines = append(machines, m)
+		}
+	}
+
+	return machines
+}
@@ -4,11 +4,9 @@ import (
 	"fmt"
 	"regexp"
 	"slices"
-	"strings"
 
 	"github.com/mbordner/aoc2025/common"
 	"github.com/mbordner/aoc2025/common/array"
-	"github.com/mbordner/aoc2025/common/expression"
 	"github.com/mbordner/aoc2025/common/files"
 	"github.com/mbordner/aoc2025/common/matrices"
 )
@@ -89,156 +87,39 @@ func (m *Machine) getAugmentedMatrix() [][]int64 {
 func (m *Machine) fewestPressesToConfigure() int {
 
 	matrix := m.getAugmentedMatrix()
-	//fmt.Println(matrix)
 	matrixRREF := matrices.ToIntegerReducedEchelonForm(matrix)
-	//fmt.Println(matrixRREF)
-
-	// remove zero rows
-	for len(matrixRREF) > 0 {
-		lastRow := matrixRREF[len(matrixRREF)-1]
-		if array.SumNumbers(lastRow) == 0 {
-			matrixRREF = matrixRREF[0 : len(matrixRREF)-1]
-		} else {
-			break
-		}
-	}
-
-	freeVariableColumns := matrices.FindFreeVariables(matrixRREF)
-
-	searchVariables := make(map[int]string)
-	for _, v := range freeVariableColumns {
-		searchVariables[v] = fmt.Sprintf("b%d", v)
-	}
-
-	expressions := make([]*expression.Parser, len(m.buttons))
-	for e := 0; e < len(matrixRREF); e++ {
-		tokens := make([]string, 0, len(matrixRREF[e]))
-		tokens = append(tokens, fmt.Sprintf("%d", matrixRREF[e][len(matrixRREF[e])-1]))
-
-		pivot := -1
-
-		for b := 0; b < len(matrixRREF[e])-1; b++ {
-			if matrixRREF[e][b] != 0 {
-				if pivot == -1 {
-					pivot = b
-				} else {
-					token := fmt.Sprintf("b%d", b)
-					if matrixRREF[e][b] != 1 {
-						token = fmt.Sprintf("(%s * %d)", token, matrixRREF[e][b])
-					}
-					tokens = append(tokens, token)
-				}
-
-			}
-		}
-
-		expr := strings.Join(tokens, " - ")
-		if matrixRREF[e][pivot] != 1 {
-			expr = fmt.Sprintf("(%s) / %d", expr, matrixRREF[e][pivot])
-		}
-		var err error
-		expressions[pivot], err = expression.NewParser(expr)
-		if err != nil {
-			panic(err)
-		}
 
+	solution, err := matrices.NewParametricSolution(matrixRREF)
+	if err != nil {
+		panic(err)
 	}
 
-	if len(searchVariables) == 0 {
-		presses := make([]int64, len(m.buttons))
-		input := make(map[string]int64)
-		for b := len(m.buttons) - 1; b >= 0; b-- {
-			if expressions[b] != nil {
-				buttonVar := fmt.Sprintf("b%d", b)
-				var err error
-				input[buttonVar], err = expressions[b].Eval(input)
-				if err != nil {
-					panic(err)
-				}
-				presses[b] = input[buttonVar]
-			}
-		}
-		for _, p := range presses {
-			if p < 0 {
-				panic("negative press")
-			}
+	// a button can't be pressed more often than the smallest counter it increases
+	lo := make([]int64, len(m.buttons))
+	hi := make([]int64, len(m.buttons))
+	for b, button := range m.buttons {
+		hi[b] = int64(slices.Max(m.jolts))
+		for _, j := range button {
+			hi[b] = min(hi[b], int64(m.jolts[j]))
 		}
-		minPresses := int(array.SumNumbers(presses))
-		fmt.Println("to get to: {", m.joltsStr, "} takes: ", minPresses, "[", presses, "] searched:", 0)
-		return minPresses
 	}
 
 	minPresses := int64(-1)
 	var minButtonPresses []int64
+	searched := 0
 
-	searchIndexes := make([]int, 0, len(searchVariables))
-	for v := range searchVariables {
-		searchIndexes = append(searchIndexes, v)
+	solutions, err := solution.IntegerSolutions(lo, hi)
+	if err != nil {
+		panic(err)
 	}
 
-	queue := make(common.Queue[string], 0, 200)
-	visited := make(common.VisitedState[string, bool])
-
-	startPresses := make([]int64, len(freeVariableColumns))
-	start := common.StrFromVals[int64](startPresses)
-
-	queue.Enqueue(start)
-	visited.Set(start, true)
-
-	searched := int64(0)
-
-	for !queue.Empty() {
-		cur := *(queue.Dequeue())
+	for presses := range solutions {
 		searched++
-		validClickCount := true
-
-		vals := common.IntVals[int64](cur)
-		input := make(map[string]int64)
-		for i, v := range vals {
-			b := searchIndexes[i]
-			input[fmt.Sprintf("b%d", b)] = v
-		}
-
-		for b := len(m.buttons) - 1; b >= 0; b-- {
-			if expressions[b] != nil {
-				buttonVar := fmt.Sprintf("b%d", b)
-				var err error
-				input[buttonVar], err = expressions[b].Eval(input)
-				if err != nil || input[buttonVar] < 0 {
-					validClickCount = false
-					break
-				}
-			}
-		}
-
-		if validClickCount {
-			presses := make([]int64, len(m.buttons))
-			for b := len(m.buttons) - 1; b >= 0; b-- {
-				buttonVar := fmt.Sprintf("b%d", b)
-				presses[b] = input[buttonVar]
-			}
-
-			pressesSum := array.SumNumbers(presses)
-			if minPresses == -1 || pressesSum < minPresses {
-				minPresses = pressesSum
-				minButtonPresses = presses
-			}
-		}
-
-		for i, v := range vals {
-			nextVals := common.CloneVals(vals)
-			nextVals[i] = v + 1
-
-			if int(nextVals[i]) > slices.Max(m.jolts) {
-				continue
-			}
-			next := common.StrFromVals(nextVals)
-			if !visited.Has(next) {
-				visited.Set(next, true)
-				queue.Enqueue(next)
-			}
+		pressesSum := array.SumNumbers(presses)
+		if minPresses == -1 || pressesSum < minPresses {
+			minPresses = pressesSum
+			minButtonPresses = slices.Clone(presses)
 		}
-
 	}
 
 	fmt.Println("to get to: {", m.joltsStr, "} takes: ", minPresses, "[", minButtonPresses, "] searched:", searched)